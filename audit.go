@@ -0,0 +1,196 @@
+package gonfig
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+)
+
+// author describes who changed param value.
+type author struct {
+	actor  string
+	source string
+
+	// rollback is true for changes made by Rollback.
+	rollback bool
+}
+
+type ctxKey int
+
+const (
+	actorKey ctxKey = iota
+	sourceKey
+)
+
+// WithActor returns context carrying actor, for example user name,
+// which changes params via UpdateContext or ParseContext.
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey, actor)
+}
+
+// WithSource returns context carrying source of changes made via
+// UpdateContext or ParseContext, for example "http" or "env".
+func WithSource(ctx context.Context, source string) context.Context {
+	return context.WithValue(ctx, sourceKey, source)
+}
+
+// authorOf returns author carried by ctx or nil.
+func authorOf(ctx context.Context) *author {
+	actor, _ := ctx.Value(actorKey).(string)
+	source, _ := ctx.Value(sourceKey).(string)
+	if actor == "" && source == "" {
+		return nil
+	}
+	return &author{actor: actor, source: source}
+}
+
+// ParseContext parses value of param identified by code as Update
+// does. The change is attributed to actor and source carried by ctx.
+func (c *Config) ParseContext(ctx context.Context, code, s string) error {
+	return c.UpdateContext(ctx, func(tx Tx) error {
+		return tx.Parse(code, s)
+	})
+}
+
+// AuditRecord describes a change of param value. Values of secrets
+// are masked. Actor and Source are empty if value was changed without
+// context, for example by Set of binded variable.
+type AuditRecord struct {
+	Time   time.Time `json:"time"`
+	Code   string    `json:"code"`
+	Old    string    `json:"old"`
+	New    string    `json:"new"`
+	Source string    `json:"source,omitempty"`
+	Actor  string    `json:"actor,omitempty"`
+}
+
+// An AuditSink receives records of param changes. Audit is called
+// synchronously by the goroutine delivering the change, see OnChange.
+type AuditSink interface {
+	Audit(r AuditRecord)
+}
+
+// auditor delivers changes of all params to sinks.
+type auditor struct {
+	mux   sync.RWMutex
+	sinks []AuditSink
+}
+
+// WithAudit makes config container send every change of param value
+// to sink. Can be called several times to add several sinks.
+func (c *Config) WithAudit(sink AuditSink) *Config {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	if c.audit != nil {
+		c.audit.mux.Lock()
+		c.audit.sinks = append(c.audit.sinks, sink)
+		c.audit.mux.Unlock()
+		return c
+	}
+
+	c.audit = &auditor{sinks: []AuditSink{sink}}
+	for i := range c.list {
+		c.audit.watch(c, c.list[i].code, c.list[i].av)
+	}
+	return c
+}
+
+func (a *auditor) watch(c *Config, code string, v Valuer) {
+	c.subscribeWith(code, v.(watchable).mem(), &subscription{record: a.record})
+}
+
+func (a *auditor) record(c Change, at time.Time, by *author) {
+	r := AuditRecord{Time: at, Code: c.Code, Old: c.Old, New: c.New}
+	if by != nil {
+		r.Actor, r.Source = by.actor, by.source
+	}
+
+	a.mux.RLock()
+	sinks := a.sinks
+	a.mux.RUnlock()
+	for _, s := range sinks {
+		s.Audit(r)
+	}
+}
+
+// AuditBuffer implements AuditSink keeping the last records in memory.
+type AuditBuffer struct {
+	mux     sync.Mutex
+	size    int
+	next    int
+	records []AuditRecord
+}
+
+// NewAuditBuffer returns buffer keeping the last size records.
+func NewAuditBuffer(size int) *AuditBuffer {
+	if size <= 0 {
+		size = DefaultHistorySize
+	}
+	return &AuditBuffer{size: size, records: make([]AuditRecord, 0, size)}
+}
+
+// Audit implements AuditSink interface.
+func (b *AuditBuffer) Audit(r AuditRecord) {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+	if len(b.records) < b.size {
+		b.records = append(b.records, r)
+		return
+	}
+	b.records[b.next] = r
+	b.next = (b.next + 1) % b.size
+}
+
+// Records returns kept records from the oldest to the latest.
+func (b *AuditBuffer) Records() []AuditRecord {
+	b.mux.Lock()
+	defer b.mux.Unlock()
+	res := make([]AuditRecord, 0, len(b.records))
+	res = append(res, b.records[b.next:]...)
+	return append(res, b.records[:b.next]...)
+}
+
+// AuditFile implements AuditSink appending records to a file
+// as JSON lines.
+type AuditFile struct {
+	mux   sync.Mutex
+	f     *os.File
+	enc   *json.Encoder
+	onerr func(error)
+}
+
+// OpenAuditFile opens or creates file at path for appending records.
+func OpenAuditFile(path string) (*AuditFile, error) {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, err
+	}
+	return &AuditFile{f: f, enc: json.NewEncoder(f)}, nil
+}
+
+// OnError sets function called if record can't be written.
+func (a *AuditFile) OnError(f func(error)) *AuditFile {
+	a.mux.Lock()
+	a.onerr = f
+	a.mux.Unlock()
+	return a
+}
+
+// Audit implements AuditSink interface.
+func (a *AuditFile) Audit(r AuditRecord) {
+	a.mux.Lock()
+	defer a.mux.Unlock()
+	if err := a.enc.Encode(r); err != nil && a.onerr != nil {
+		a.onerr(err)
+	}
+}
+
+// Close closes the file.
+func (a *AuditFile) Close() error {
+	a.mux.Lock()
+	defer a.mux.Unlock()
+	return a.f.Close()
+}
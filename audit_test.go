@@ -0,0 +1,152 @@
+package gonfig_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/axkit/gonfig"
+)
+
+func TestConfig_WithAudit(t *testing.T) {
+
+	type C struct {
+		Max  gonfig.Int    `cfg:"max_connections" default:"10"`
+		Pass gonfig.Secret `cfg:"password"`
+	}
+
+	buf := gonfig.NewAuditBuffer(2)
+	cfg := gonfig.New().WithAudit(buf)
+
+	var c C
+	cfg.BindStruct(&c)
+
+	ctx := gonfig.WithActor(gonfig.WithSource(context.Background(), "http"), "alice")
+	if err := cfg.ParseContext(ctx, "max_connections", "20"); err != nil {
+		t.Fatal(err)
+	}
+	c.Pass.Set("qwerty")
+
+	rs := buf.Records()
+	if len(rs) != 2 {
+		t.Fatalf("expected 2 records, got %v", rs)
+	}
+
+	r := rs[0]
+	if r.Code != "max_connections" || r.Old != "10" || r.New != "20" ||
+		r.Actor != "alice" || r.Source != "http" || r.Time.IsZero() {
+		t.Errorf("unexpected record %+v", r)
+	}
+
+	r = rs[1]
+	if r.Code != "password" || r.New != gonfig.SecretMask || r.Actor != "" {
+		t.Errorf("unexpected record %+v", r)
+	}
+
+	// ring buffer keeps the last records.
+	c.Max.Set(30)
+	if rs := buf.Records(); len(rs) != 2 || rs[0].Code != "password" || rs[1].New != "30" {
+		t.Errorf("unexpected records %v", rs)
+	}
+}
+
+// sinkFunc implements AuditSink by function.
+type sinkFunc func(r gonfig.AuditRecord)
+
+func (f sinkFunc) Audit(r gonfig.AuditRecord) { f(r) }
+
+func TestConfig_WithAudit_BindDefaults(t *testing.T) {
+
+	var cfg *gonfig.Config
+	var got []string
+	cfg = gonfig.New().WithHistory(10).WithAudit(sinkFunc(func(r gonfig.AuditRecord) {
+		// container must not be locked while sinks are called.
+		v, _ := cfg.Get(r.Code)
+		got = append(got, r.Code+"="+gonfig.Plain(v))
+	}))
+
+	var c struct {
+		Port gonfig.Int    `cfg:"port" default:"80"`
+		Host gonfig.String `cfg:"host" default:"localhost"`
+	}
+
+	done := make(chan []error)
+	go func() { done <- cfg.BindStruct(&c) }()
+	select {
+	case errs := <-done:
+		if len(errs) > 0 {
+			t.Fatal(errs)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("BindStruct deadlocked")
+	}
+
+	if len(got) != 2 || got[0] != "port=80" || got[1] != "host=localhost" {
+		t.Errorf("unexpected records %v", got)
+	}
+	if h := cfg.History(); len(h) != 2 {
+		t.Errorf("unexpected history %v", h)
+	}
+}
+
+func TestAuditFile(t *testing.T) {
+
+	path := filepath.Join(t.TempDir(), "audit.log")
+	f, err := gonfig.OpenAuditFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := gonfig.New()
+	cfg.MustParam("port", gonfig.AInt)
+	cfg.WithAudit(f)
+
+	ctx := gonfig.WithActor(context.Background(), "bob")
+	cfg.ParseContext(ctx, "port", "80")
+	cfg.ParseContext(ctx, "port", "80")
+	cfg.MustParam("port", gonfig.AInt).Parse("8080")
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	fd, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fd.Close()
+
+	var rs []gonfig.AuditRecord
+	sc := bufio.NewScanner(fd)
+	for sc.Scan() {
+		var r gonfig.AuditRecord
+		if err := json.Unmarshal(sc.Bytes(), &r); err != nil {
+			t.Fatal(err)
+		}
+		rs = append(rs, r)
+	}
+
+	if len(rs) != 2 || rs[0].Actor != "bob" || rs[0].New != "80" || rs[1].Old != "80" || rs[1].Actor != "" {
+		t.Errorf("unexpected records %+v", rs)
+	}
+}
+
+func TestLoader_AuditSource(t *testing.T) {
+
+	buf := gonfig.NewAuditBuffer(10)
+	cfg := gonfig.New().WithAudit(buf)
+	cfg.MustParam("limit", gonfig.AInt)
+
+	l := gonfig.NewLoader().Add("defaults", gonfig.MapSource{"limit": "5"})
+	if err := l.Load(cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	rs := buf.Records()
+	if len(rs) != 1 || rs[0].Source != "defaults" {
+		t.Errorf("unexpected records %+v", rs)
+	}
+}
@@ -2,6 +2,7 @@ package gonfig
 
 import (
 	"errors"
+	"strconv"
 	"strings"
 	"sync/atomic"
 	"unsafe"
@@ -15,6 +16,9 @@ var NonBindedBool bool = false
 // implemented using int32 inside.
 type Bool struct {
 	ref *int32
+
+	// c is container the param belongs to.
+	c *Config
 }
 
 // ErrInvalidBool indicated failed parsing.
@@ -22,7 +26,7 @@ var ErrInvalidBool = errors.New("invalid value. Expected true or false")
 
 // NewBool returns atomic bool.
 func NewBool() *Bool {
-	return &Bool{new(int32)}
+	return &Bool{ref: new(int32)}
 }
 
 // Kind return ABool.
@@ -32,6 +36,18 @@ func (a *Bool) Kind() AKind {
 
 // Set assigns value atomically.Initializes if was not before.
 func (a *Bool) Set(b bool) {
+	if a.owner().rejected(a.mem(), func() Valuer {
+		n := NewBool()
+		n.Set(b)
+		return n
+	}) {
+		return
+	}
+	a.set(b, nil)
+}
+
+// set is like Set, the change is attributed to by.
+func (a *Bool) set(b bool, by *author) {
 
 	var i int32
 	if b {
@@ -39,6 +55,13 @@ func (a *Bool) Set(b bool) {
 	}
 
 	ptr := atomic.LoadPointer((*unsafe.Pointer)(unsafe.Pointer(&a.ref)))
+	if n := a.owner().notifierOf(ptr); n != nil {
+		n.change(by, func() (string, string, bool) {
+			old := atomic.SwapInt32((*int32)(ptr), i)
+			return strconv.FormatBool(old == 1), strconv.FormatBool(b), old != i
+		})
+		return
+	}
 	if ptr != nil {
 		atomic.StoreInt32((*int32)(ptr), i)
 		return
@@ -64,11 +87,28 @@ func (a *Bool) IsBinded() bool {
 	return atomic.LoadPointer((*unsafe.Pointer)(unsafe.Pointer(&a.ref))) != nil
 }
 
+func (a *Bool) mem() unsafe.Pointer {
+	return atomic.LoadPointer((*unsafe.Pointer)(unsafe.Pointer(&a.ref)))
+}
+
+func (a *Bool) owner() *Config {
+	return loadOwner(&a.c)
+}
+
+func (a *Bool) own(c *Config) {
+	storeOwner(&a.c, c)
+}
+
+func (a *Bool) assign(v Valuer, by *author) {
+	a.set(v.(*Bool).Val(), by)
+}
+
 // Bind binds current atomic variable to variable identified by to.
 // As a result two Bool address to the same variable.
 func (a *Bool) Bind(b *Bool) {
 	ptr := atomic.LoadPointer((*unsafe.Pointer)(unsafe.Pointer(&b.ref)))
 	if ptr != nil {
+		storeOwner(&a.c, b.owner())
 		atomic.StorePointer((*unsafe.Pointer)(unsafe.Pointer(&a.ref)), ptr)
 	}
 }
@@ -100,16 +140,25 @@ func (a *Bool) UnmarshalJSON(buf []byte) error {
 
 // Parse converts input argument and assigns to value.
 // Accepts Y,N, T,F, TRUE,FALSE, YES,NO, 1,0 in any register.
+// Value is not changed if it violates validation rules.
 func (a *Bool) Parse(s string) error {
-	s = strings.ToLower(s)
-	switch s {
+	var b bool
+	switch strings.ToLower(s) {
 	case "y", "t", "true", "yes", "1":
-		a.Set(true)
+		b = true
 	case "n", "f", "false", "no", "0":
-		a.Set(false)
 	default:
 		return ErrInvalidBool
 	}
+
+	if vr := a.owner().validatorOf(a.mem()); vr != nil {
+		n := NewBool()
+		n.Set(b)
+		if err := vr.check(n); err != nil {
+			return err
+		}
+	}
+	a.Set(b)
 	return nil
 }
 
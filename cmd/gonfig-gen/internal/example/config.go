@@ -0,0 +1,43 @@
+// Package example is bound by code generated by gonfig-gen.
+package example
+
+import (
+	"errors"
+	"net"
+
+	"github.com/axkit/gonfig"
+)
+
+//go:generate go run github.com/axkit/gonfig/cmd/gonfig-gen -type Config
+
+// AIP is a kind of params holding IP address.
+var AIP = gonfig.RegisterKind("AIP", func(s string) (net.IP, error) {
+	ip := net.ParseIP(s)
+	if ip == nil {
+		return nil, errors.New("invalid ip address")
+	}
+	return ip, nil
+}, net.IP.String)
+
+// Listener is a nested struct.
+type Listener struct {
+	Port gonfig.Int    `cfg:"port" default:"8080" min:"1"`
+	Host gonfig.String `cfg:"host" default:"localhost"`
+}
+
+// Config is bound by generated Config.Bind.
+type Config struct {
+	Listener
+
+	Timeout gonfig.Duration      `cfg:"timeout" default:"5s" desc:"Request timeout"`
+	Hosts   gonfig.StringSlice   `cfg:"hosts" sep:";"`
+	Token   gonfig.Secret        `cfg:"token"`
+	IP      gonfig.Value[net.IP] `cfg:"ip"`
+	DB      *DB
+	Skipped int
+}
+
+// DB is bound if pointer is not nil.
+type DB struct {
+	Pool gonfig.Int `cfg:"db_pool" default:"10" static:"true"`
+}
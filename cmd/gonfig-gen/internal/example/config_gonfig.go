@@ -0,0 +1,40 @@
+// Code generated by gonfig-gen; DO NOT EDIT.
+
+package example
+
+import (
+	"errors"
+
+	"github.com/axkit/gonfig"
+)
+
+// Codes of params bound by Config.Bind.
+const (
+	ConfigPortCode    = "port"
+	ConfigHostCode    = "host"
+	ConfigTimeoutCode = "timeout"
+	ConfigHostsCode   = "hosts"
+	ConfigTokenCode   = "token"
+	ConfigIPCode      = "ip"
+	ConfigDBPoolCode  = "db_pool"
+)
+
+// Bind binds fields of c to params of cfg as cfg.BindStruct does.
+func (c *Config) Bind(cfg gonfig.Configer) error {
+	fb, ok := cfg.(gonfig.FieldBinder)
+	if !ok {
+		return errors.New("config does not implement gonfig.FieldBinder")
+	}
+
+	var errs []error
+	errs = append(errs, fb.BindField(ConfigPortCode, &c.Listener.Port, `cfg:"port" default:"8080" min:"1"`)...)
+	errs = append(errs, fb.BindField(ConfigHostCode, &c.Listener.Host, `cfg:"host" default:"localhost"`)...)
+	errs = append(errs, fb.BindField(ConfigTimeoutCode, &c.Timeout, `cfg:"timeout" default:"5s" desc:"Request timeout"`)...)
+	errs = append(errs, fb.BindField(ConfigHostsCode, &c.Hosts, `cfg:"hosts" sep:";"`)...)
+	errs = append(errs, fb.BindField(ConfigTokenCode, &c.Token, `cfg:"token"`)...)
+	errs = append(errs, fb.BindField(ConfigIPCode, &c.IP, `cfg:"ip"`)...)
+	if c.DB != nil {
+		errs = append(errs, fb.BindField(ConfigDBPoolCode, &c.DB.Pool, `cfg:"db_pool" default:"10" static:"true"`)...)
+	}
+	return errors.Join(errs...)
+}
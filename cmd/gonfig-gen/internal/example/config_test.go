@@ -0,0 +1,45 @@
+package example_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/axkit/gonfig"
+	"github.com/axkit/gonfig/cmd/gonfig-gen/internal/example"
+)
+
+func TestConfig_Bind(t *testing.T) {
+
+	c := example.Config{DB: &example.DB{}}
+	cfg := gonfig.New()
+	cfg.MustParam(example.ConfigIPCode, example.AIP).Parse("10.0.0.1")
+
+	if err := c.Bind(cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	if c.Port.Val() != 8080 || c.Host.Val() != "localhost" || c.Timeout.Val() != 5*time.Second ||
+		c.IP.Val().String() != "10.0.0.1" || c.DB.Pool.Val() != 10 {
+		t.Errorf("unexpected values %d %s %v %v %d", c.Port.Val(), c.Host.Val(), c.Timeout.Val(), c.IP.Val(), c.DB.Pool.Val())
+	}
+
+	cfg.MustParam(example.ConfigHostsCode, gonfig.AStringSlice).Parse("a;b")
+	if len(c.Hosts.Val()) != 2 {
+		t.Errorf("unexpected hosts %v", c.Hosts.Val())
+	}
+
+	if info, _ := cfg.Info(example.ConfigDBPoolCode); !info.Static {
+		t.Error("expected static param")
+	}
+
+	// validation rules are applied.
+	if err := c.Port.Parse("0"); err == nil {
+		t.Error("expected validation error")
+	}
+
+	// nil nested struct is skipped.
+	var c2 example.Config
+	if err := c2.Bind(gonfig.New()); err != nil {
+		t.Fatal(err)
+	}
+}
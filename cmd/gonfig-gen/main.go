@@ -0,0 +1,256 @@
+// Command gonfig-gen generates code binding config structs to
+// gonfig container without reflection.
+//
+// Usage:
+//
+//	gonfig-gen -type NAME[,NAME...] [-output FILE] [DIR]
+//
+// It's intended to be run by go generate:
+//
+//	//go:generate gonfig-gen -type Config
+//
+// For every struct type NAME declared in Go files of DIR, current
+// directory by default, gonfig-gen writes method
+//
+//	func (c *NAME) Bind(cfg gonfig.Configer) error
+//
+// binding fields having tag "cfg" as gonfig.Config.BindStruct does,
+// and constants holding codes of params, named after type and path
+// of field, for example ConfigPortCode. Fields of nested structs
+// declared in the same package are bound as well. Bind returns error
+// if cfg does not implement gonfig.FieldBinder.
+//
+// Unlike BindStruct, fields with tag "cfg" of types not implementing
+// gonfig.Valuer and tags "cfg" having options, such as prefix,
+// are reported as errors.
+//
+// Output is written to FILE, by default to lower cased first NAME
+// followed by "_gonfig.go".
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"go/format"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/axkit/gonfig/internal/scan"
+)
+
+// valuers lists gonfig types implementing gonfig.Valuer.
+var valuers = map[string]bool{
+	"Int":         true,
+	"Bool":        true,
+	"String":      true,
+	"Float":       true,
+	"Duration":    true,
+	"StringSlice": true,
+	"IntSlice":    true,
+	"Secret":      true,
+	"Value":       true,
+}
+
+func main() {
+	if err := run(os.Args[1:], os.Stderr); err != nil {
+		fmt.Fprintln(os.Stderr, "gonfig-gen:", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stderr io.Writer) error {
+	fs := flag.NewFlagSet("gonfig-gen", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	typs := fs.String("type", "", "comma separated list of struct type names")
+	output := fs.String("output", "", "output file name")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *typs == "" {
+		return errors.New("-type is required")
+	}
+
+	dir := "."
+	switch fs.NArg() {
+	case 0:
+	case 1:
+		dir = fs.Arg(0)
+	default:
+		return errors.New("single directory expected")
+	}
+
+	names := strings.Split(*typs, ",")
+	out := *output
+	if out == "" {
+		out = strings.ToLower(names[0]) + "_gonfig.go"
+	}
+	if !filepath.IsAbs(out) {
+		out = filepath.Join(dir, out)
+	}
+
+	pkg, err := scan.Dir(dir)
+	if err != nil {
+		return err
+	}
+
+	src, err := generate(pkg, names)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(out, src, 0644)
+}
+
+// binding is a field bound to param.
+type binding struct {
+	konst string
+	code  string
+	path  string
+	tag   string
+}
+
+// block is a sequence of bindings and nested blocks in order
+// of fields, optionally guarded by not nil check of pointer
+// to nested struct.
+type block struct {
+	guard string
+	items []item
+}
+
+// item is either binding or nested block.
+type item struct {
+	b   *binding
+	blk *block
+}
+
+// generate returns formatted source binding struct types names.
+func generate(pkg *scan.Package, names []string) ([]byte, error) {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// Code generated by gonfig-gen; DO NOT EDIT.\n\npackage %s\n\n", pkg.Name)
+	fmt.Fprintf(&b, "import (\n\t\"errors\"\n\n\t%q\n)\n", scan.ImportPath)
+
+	for _, name := range names {
+		st, ok := pkg.Struct(name)
+		if !ok {
+			return nil, fmt.Errorf("struct type %s not found", name)
+		}
+
+		var root block
+		if err := walk(pkg, st, name, "c", &root, map[string]bool{name: true}); err != nil {
+			return nil, err
+		}
+
+		var consts []binding
+		collect(&root, &consts)
+		if len(consts) > 0 {
+			fmt.Fprintf(&b, "\n// Codes of params bound by %s.Bind.\nconst (\n", name)
+			for _, c := range consts {
+				fmt.Fprintf(&b, "\t%s = %q\n", c.konst, c.code)
+			}
+			b.WriteString(")\n")
+		}
+
+		fmt.Fprintf(&b, "\n// Bind binds fields of c to params of cfg as cfg.BindStruct does.\n")
+		fmt.Fprintf(&b, "func (c *%s) Bind(cfg gonfig.Configer) error {\n", name)
+		b.WriteString("\tfb, ok := cfg.(gonfig.FieldBinder)\n\tif !ok {\n\t\treturn errors.New(\"config does not implement gonfig.FieldBinder\")\n\t}\n\n\tvar errs []error\n")
+		write(&b, &root)
+		b.WriteString("\treturn errors.Join(errs...)\n}\n")
+	}
+
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("format generated code: %w\n%s", err, b.Bytes())
+	}
+	return src, nil
+}
+
+// walk appends bindings of fields of struct st accessed by path
+// to blk. konst is a prefix of constant names.
+func walk(pkg *scan.Package, st *scan.Struct, konst, path string, blk *block, visiting map[string]bool) error {
+	for _, f := range st.Fields {
+		fpath := path + "." + f.Name
+		code := f.Tag.Get("cfg")
+
+		if _, opts, _ := strings.Cut(code, ","); opts != "" {
+			return fmt.Errorf("%s.%s: options of tag cfg are not supported, use BindStruct", st.Name, f.Name)
+		}
+
+		if code != "" {
+			if !valuers[f.Gonfig] {
+				return fmt.Errorf("%s.%s: type %s of field with tag cfg does not implement gonfig.Valuer", st.Name, f.Name, f.Type)
+			}
+			blk.items = append(blk.items, item{b: &binding{
+				konst: konst + f.Name + "Code",
+				code:  code,
+				path:  fpath,
+				tag:   string(f.Tag),
+			}})
+			continue
+		}
+
+		typ := strings.TrimPrefix(f.Type, "*")
+		nested, ok := pkg.Struct(typ)
+		if !ok {
+			continue
+		}
+		if visiting[typ] {
+			return fmt.Errorf("%s.%s: recursive type %s", st.Name, f.Name, typ)
+		}
+		visiting[typ] = true
+
+		nkonst := konst + f.Name
+		if f.Embedded {
+			nkonst = konst
+		}
+
+		if typ != f.Type {
+			nb := &block{guard: fpath}
+			if err := walk(pkg, nested, nkonst, fpath, nb, visiting); err != nil {
+				return err
+			}
+			blk.items = append(blk.items, item{blk: nb})
+		} else if err := walk(pkg, nested, nkonst, fpath, blk, visiting); err != nil {
+			return err
+		}
+		delete(visiting, typ)
+	}
+	return nil
+}
+
+func collect(blk *block, res *[]binding) {
+	for _, it := range blk.items {
+		if it.b != nil {
+			*res = append(*res, *it.b)
+			continue
+		}
+		collect(it.blk, res)
+	}
+}
+
+func write(b *bytes.Buffer, blk *block) {
+	if blk.guard != "" {
+		fmt.Fprintf(b, "if %s != nil {\n", blk.guard)
+	}
+	for _, it := range blk.items {
+		if x := it.b; x != nil {
+			fmt.Fprintf(b, "errs = append(errs, fb.BindField(%s, &%s, %s)...)\n", x.konst, x.path, quote(x.tag))
+			continue
+		}
+		write(b, it.blk)
+	}
+	if blk.guard != "" {
+		b.WriteString("}\n")
+	}
+}
+
+// quote returns s as raw string literal if possible.
+func quote(s string) string {
+	if strings.Contains(s, "`") {
+		return strconv.Quote(s)
+	}
+	return "`" + s + "`"
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/axkit/gonfig/internal/scan"
+)
+
+func TestGenerate_Example(t *testing.T) {
+
+	pkg, err := scan.Dir("internal/example")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	src, err := generate(pkg, []string{"Config"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want, err := os.ReadFile("internal/example/config_gonfig.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(src) != string(want) {
+		t.Errorf("generated code is outdated, run go generate ./...\n%s", src)
+	}
+}
+
+func TestRun(t *testing.T) {
+
+	dir := t.TempDir()
+	write := func(src string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, "a.go"), []byte(src), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write("package app\n\nimport g \"github.com/axkit/gonfig\"\n\ntype App struct {\n\tPort g.Int `cfg:\"port\"`\n}\n")
+	if err := run([]string{"-type", "App", dir}, os.Stderr); err != nil {
+		t.Fatal(err)
+	}
+	src, err := os.ReadFile(filepath.Join(dir, "app_gonfig.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(src), "fb.BindField(AppPortCode, &c.Port, `cfg:\"port\"`)") {
+		t.Errorf("unexpected code\n%s", src)
+	}
+
+	// embedded pointer is bound by name of type.
+	write("package app\n\nimport \"github.com/axkit/gonfig\"\n\ntype App struct {\n\t*Listener\n}\n\ntype Listener struct {\n\tPort gonfig.Int `cfg:\"port\"`\n}\n")
+	if err := run([]string{"-type", "App", dir}, os.Stderr); err != nil {
+		t.Fatal(err)
+	}
+	if src, err = os.ReadFile(filepath.Join(dir, "app_gonfig.go")); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(src), "if c.Listener != nil {\n\t\terrs = append(errs, fb.BindField(AppPortCode, &c.Listener.Port, `cfg:\"port\"`)...)") {
+		t.Errorf("unexpected code\n%s", src)
+	}
+
+	for _, tc := range []struct {
+		src  string
+		want string
+	}{
+		{"package app\n\ntype App struct {\n\tPort int `cfg:\"port\"`\n}\n", "does not implement gonfig.Valuer"},
+		{"package app\n\ntype App struct {\n\tNext *App\n}\n", "recursive type"},
+		{"package app\n\ntype App struct {\n\tAdmin Other `cfg:\"admin,prefix\"`\n}\n\ntype Other struct{}\n", "options of tag cfg"},
+		{"package app\n\ntype Other struct{}\n", "not found"},
+	} {
+		write(tc.src)
+		err := run([]string{"-type", "App", "-output", "out.go", dir}, os.Stderr)
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("expected error %q, got %v", tc.want, err)
+		}
+	}
+}
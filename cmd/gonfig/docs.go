@@ -0,0 +1,200 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/axkit/gonfig"
+	"github.com/axkit/gonfig/internal/scan"
+)
+
+// value stands for types held by gonfig.Value. Their kinds are
+// registered by RegisterKind at run time and unknown to docs.
+type value string
+
+// aValue is a kind of fields of type gonfig.Value.
+var aValue = gonfig.RegisterKind("Value", func(s string) (value, error) { return value(s), nil }, nil)
+
+// kinds maps gonfig types to kinds.
+var kinds = map[string]gonfig.AKind{
+	"Int":         gonfig.AInt,
+	"Bool":        gonfig.ABool,
+	"String":      gonfig.AString,
+	"Float":       gonfig.AFloat,
+	"Duration":    gonfig.ADuration,
+	"StringSlice": gonfig.AStringSlice,
+	"IntSlice":    gonfig.AIntSlice,
+	"Secret":      gonfig.ASecret,
+	"Value":       aValue,
+}
+
+// plainKinds maps plain field types to kinds. Other plain
+// types are held by AString params.
+var plainKinds = map[string]gonfig.AKind{
+	"int":           gonfig.AInt,
+	"int64":         gonfig.AInt,
+	"uint":          gonfig.AInt,
+	"bool":          gonfig.ABool,
+	"string":        gonfig.AString,
+	"float64":       gonfig.AFloat,
+	"time.Duration": gonfig.ADuration,
+	"[]string":      gonfig.AStringSlice,
+	"[]int":         gonfig.AIntSlice,
+}
+
+var docFormats = map[string]gonfig.DocFormat{
+	"md":   gonfig.DocMarkdown,
+	"html": gonfig.DocHTML,
+	"man":  gonfig.DocMan,
+}
+
+// docs writes documentation of params declared by struct tags
+// in Go files of directory.
+func docs(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("docs", flag.ContinueOnError)
+	format := fs.String("format", "md", "output format: md, html or man")
+	prefix := fs.String("prefix", "", "prefix of environment variables")
+	title := fs.String("title", "", "document title")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	f, ok := docFormats[*format]
+	if !ok {
+		return fmt.Errorf("unknown format %s", *format)
+	}
+
+	dir := "."
+	switch fs.NArg() {
+	case 0:
+	case 1:
+		dir = fs.Arg(0)
+	default:
+		return fmt.Errorf("single directory expected")
+	}
+
+	pkg, err := scan.Dir(dir)
+	if err != nil {
+		return err
+	}
+
+	infos, err := paramInfos(pkg)
+	if err != nil {
+		return err
+	}
+
+	d := gonfig.Documenter{Format: f, Title: *title, EnvPrefix: *prefix}
+	return d.WriteInfo(stdout, infos)
+}
+
+// paramInfos returns descriptions of fields having tag cfg.
+// Fields of nested structs are described with codes bound by
+// their outermost struct. Items of slices and maps are
+// denoted by "*".
+func paramInfos(pkg *scan.Package) ([]gonfig.ParamInfo, error) {
+	nested := make(map[string]bool)
+	for _, st := range pkg.Structs {
+		for _, f := range st.Fields {
+			if typ, _, ok := nestedType(pkg, f); ok {
+				nested[typ] = true
+			}
+		}
+	}
+
+	var res []gonfig.ParamInfo
+	seen := make(map[string]bool)
+	for i := range pkg.Structs {
+		st := &pkg.Structs[i]
+		if nested[st.Name] {
+			continue
+		}
+		if err := describe(pkg, st, "", &res, seen, map[string]bool{st.Name: true}); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+// nestedType returns name of struct declared in pkg which fields
+// are bound with field f, and true if items of slice or map are
+// bound.
+func nestedType(pkg *scan.Package, f scan.Field) (string, bool, bool) {
+	_, opts, _ := strings.Cut(f.Tag.Get("cfg"), ",")
+	typ := strings.TrimPrefix(f.Type, "*")
+
+	items := false
+	if opts == "prefix" {
+		for _, p := range []string{"[]", "map[string]"} {
+			if t := strings.TrimPrefix(typ, p); t != typ {
+				typ, items = strings.TrimPrefix(t, "*"), true
+				break
+			}
+		}
+	}
+
+	if _, ok := pkg.Struct(typ); !ok {
+		return "", false, false
+	}
+	return typ, items, true
+}
+
+// describe appends descriptions of fields of st bound with codes
+// having prefix.
+func describe(pkg *scan.Package, st *scan.Struct, prefix string, res *[]gonfig.ParamInfo, seen, visiting map[string]bool) error {
+	for _, f := range st.Fields {
+		code, opts, _ := strings.Cut(f.Tag.Get("cfg"), ",")
+
+		if typ, items, ok := nestedType(pkg, f); ok {
+			if visiting[typ] {
+				continue
+			}
+			p := prefix
+			if opts == "prefix" {
+				p += code + gonfig.DefaultPrefixSeparator
+				if items {
+					p += "*" + gonfig.DefaultPrefixSeparator
+				}
+			}
+			nst, _ := pkg.Struct(typ)
+			visiting[typ] = true
+			if err := describe(pkg, nst, p, res, seen, visiting); err != nil {
+				return err
+			}
+			delete(visiting, typ)
+			continue
+		}
+
+		code = prefix + code
+		if code == prefix || seen[code] {
+			continue
+		}
+		seen[code] = true
+
+		info := gonfig.ParamInfo{
+			Code:    code,
+			Kind:    kinds[f.Gonfig],
+			Default: f.Tag.Get("default"),
+			Desc:    f.Tag.Get("desc"),
+			Unit:    f.Tag.Get("unit"),
+			Group:   f.Tag.Get("group"),
+		}
+		if f.Gonfig == "" {
+			// plain fields are populated once by BindStruct.
+			info.Kind, info.Static = gonfig.AString, true
+			if ak, ok := plainKinds[f.Type]; ok {
+				info.Kind = ak
+			}
+		}
+		if s := f.Tag.Get("static"); s != "" {
+			var err error
+			if info.Static, err = strconv.ParseBool(s); err != nil {
+				return fmt.Errorf("%s.%s: invalid tag static: %w", st.Name, f.Name, err)
+			}
+		}
+		*res = append(*res, info)
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+// Command gonfig is a companion tool of package gonfig.
+//
+// Usage:
+//
+//	gonfig keygen -key FILE
+//	gonfig encrypt -key FILE [VALUE]
+//	gonfig docs [-format md|html|man] [-prefix PREFIX] [-title TITLE] [DIR]
+//
+// keygen writes new random key to FILE.
+//
+// encrypt prints VALUE, or standard input if VALUE is omitted,
+// encrypted by the key from FILE in form enc:<base64> accepted
+// by gonfig sources configured with decrypter.
+//
+// docs prints documentation of params declared by struct tags cfg,
+// default, desc, unit, group and static in Go files of DIR, current
+// directory by default. PREFIX is a prefix of environment variables.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/axkit/gonfig"
+)
+
+const usage = `Usage:
+  gonfig keygen -key FILE
+  gonfig encrypt -key FILE [VALUE]
+  gonfig docs [-format md|html|man] [-prefix PREFIX] [-title TITLE] [DIR]
+`
+
+func main() {
+	if err := run(os.Args[1:], os.Stdin, os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "gonfig:", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stdin io.Reader, stdout io.Writer) error {
+	if len(args) == 0 {
+		return errors.New("command expected\n" + usage)
+	}
+
+	switch args[0] {
+	case "keygen":
+		return keygen(args[1:], stdout)
+	case "encrypt":
+		return encrypt(args[1:], stdin, stdout)
+	case "docs":
+		return docs(args[1:], stdout)
+	case "help", "-h", "--help":
+		fmt.Fprint(stdout, usage)
+		return nil
+	}
+	return fmt.Errorf("unknown command %s\n%s", args[0], usage)
+}
+
+func keygen(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("keygen", flag.ContinueOnError)
+	path := fs.String("key", "", "key file to create")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *path == "" {
+		return errors.New("-key is required")
+	}
+
+	key, err := gonfig.GenerateKey()
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(*path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintln(f, key); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func encrypt(args []string, stdin io.Reader, stdout io.Writer) error {
+	fs := flag.NewFlagSet("encrypt", flag.ContinueOnError)
+	path := fs.String("key", "", "key file")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *path == "" {
+		return errors.New("-key is required")
+	}
+
+	e, err := gonfig.LoadAESGCM(*path)
+	if err != nil {
+		return err
+	}
+
+	var plain string
+	switch fs.NArg() {
+	case 0:
+		buf, err := io.ReadAll(stdin)
+		if err != nil {
+			return err
+		}
+		plain = strings.TrimRight(string(buf), "\r\n")
+	case 1:
+		plain = fs.Arg(0)
+	default:
+		return errors.New("single value expected")
+	}
+
+	s, err := gonfig.EncryptValue(e, plain)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(stdout, s)
+	return err
+}
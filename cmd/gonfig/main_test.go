@@ -0,0 +1,110 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/axkit/gonfig"
+)
+
+func TestRun_Encrypt(t *testing.T) {
+
+	path := filepath.Join(t.TempDir(), "key")
+	if err := run([]string{"keygen", "-key", path}, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := run([]string{"keygen", "-key", path}, nil, nil); err == nil {
+		t.Error("existing key must not be overwritten")
+	}
+
+	a, err := gonfig.LoadAESGCM(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, c := range []struct {
+		args  []string
+		stdin string
+	}{
+		{[]string{"encrypt", "-key", path, "qwerty"}, ""},
+		{[]string{"encrypt", "-key", path}, "qwerty\n"},
+	} {
+		var out bytes.Buffer
+		if err := run(c.args, strings.NewReader(c.stdin), &out); err != nil {
+			t.Fatal(err)
+		}
+
+		plain, err := gonfig.DecryptValue(a, strings.TrimSpace(out.String()))
+		if err != nil || plain != "qwerty" {
+			t.Errorf("unexpected result %s, %v", plain, err)
+		}
+	}
+
+	if err := run([]string{"encrypt", "qwerty"}, nil, nil); err == nil {
+		t.Error("error expected without key")
+	}
+
+	if err := run([]string{"unknown"}, nil, nil); err == nil {
+		t.Error("error expected for unknown command")
+	}
+}
+
+func TestRun_Docs(t *testing.T) {
+
+	dir := t.TempDir()
+	src := `package app
+
+import (
+	"net"
+
+	cfg "github.com/axkit/gonfig"
+)
+
+type Config struct {
+	Port    cfg.Int    ` + "`cfg:\"port\" default:\"8080\" desc:\"Listen port\" static:\"true\"`" + `
+	Timeout cfg.Duration ` + "`cfg:\"timeout\" default:\"5s\"`" + `
+	Workers int ` + "`cfg:\"workers\" default:\"4\"`" + `
+	IP      cfg.Value[net.IP] ` + "`cfg:\"ip\"`" + `
+	Admin   Listener   ` + "`cfg:\"admin,prefix\"`" + `
+	Servers []Listener ` + "`cfg:\"servers,prefix\"`" + `
+	skipped int
+}
+
+type Listener struct {
+	Port cfg.Int ` + "`cfg:\"port\"`" + `
+}
+`
+	if err := os.WriteFile(filepath.Join(dir, "config.go"), []byte(src), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if err := run([]string{"docs", "-prefix", "APP_", dir}, nil, &out); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, w := range []string{
+		"| `port` | AInt | `8080` | `APP_PORT` | no | Listen port |",
+		"| `timeout` | ADuration | `5s` | `APP_TIMEOUT` | yes |  |",
+		"| `workers` | AInt | `4` | `APP_WORKERS` | no |  |",
+		"| `ip` | Value |  | `APP_IP` | yes |  |",
+		"| `admin.port` | AInt |  | `APP_ADMIN__PORT` | yes |  |",
+		"| `servers.*.port` | AInt |  | `APP_SERVERS__*__PORT` | yes |  |",
+	} {
+		if !strings.Contains(out.String(), w) {
+			t.Errorf("expected %q in\n%s", w, out.String())
+		}
+	}
+
+	if strings.Contains(out.String(), "| `port` | AInt |  |") {
+		t.Errorf("unexpected param of nested struct\n%s", out.String())
+	}
+
+	if err := run([]string{"docs", "-format", "pdf", dir}, nil, &out); err == nil {
+		t.Error("expected error")
+	}
+}
@@ -0,0 +1,192 @@
+package gonfig
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// EncryptedPrefix marks encrypted values: enc:<base64>.
+const EncryptedPrefix = "enc:"
+
+// ErrInvalidKey raises when key has wrong size.
+var ErrInvalidKey = errors.New("invalid key size, expected 16, 24 or 32 bytes")
+
+// A Decrypter is an interface wrapping a single method Decrypt.
+//
+// Decrypt returns plain text of ciphertext.
+type Decrypter interface {
+	Decrypt(ciphertext []byte) ([]byte, error)
+}
+
+// An Encrypter is an interface wrapping a single method Encrypt.
+//
+// Encrypt returns ciphertext of plain text.
+type Encrypter interface {
+	Encrypt(plain []byte) ([]byte, error)
+}
+
+// AESGCM implements Encrypter and Decrypter using AES in GCM mode.
+// Ciphertext is prefixed by random nonce.
+type AESGCM struct {
+	aead cipher.AEAD
+}
+
+// NewAESGCM returns AESGCM. Key must be 16, 24 or 32 bytes long.
+func NewAESGCM(key []byte) (*AESGCM, error) {
+	switch len(key) {
+	case 16, 24, 32:
+	default:
+		return nil, ErrInvalidKey
+	}
+
+	b, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := cipher.NewGCM(b)
+	if err != nil {
+		return nil, err
+	}
+	return &AESGCM{aead: aead}, nil
+}
+
+// LoadAESGCM returns AESGCM with key read from the file.
+// The file contains base64 encoded key.
+func LoadAESGCM(path string) (*AESGCM, error) {
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(buf)))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return NewAESGCM(key)
+}
+
+// GenerateKey returns base64 encoded random key of 32 bytes
+// accepted by LoadAESGCM.
+func GenerateKey() (string, error) {
+	key := make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(key), nil
+}
+
+// Encrypt implements Encrypter interface.
+func (a *AESGCM) Encrypt(plain []byte) ([]byte, error) {
+	nonce := make([]byte, a.aead.NonceSize(), a.aead.NonceSize()+len(plain)+a.aead.Overhead())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return a.aead.Seal(nonce, nonce, plain, nil), nil
+}
+
+// Decrypt implements Decrypter interface.
+func (a *AESGCM) Decrypt(ciphertext []byte) ([]byte, error) {
+	ns := a.aead.NonceSize()
+	if len(ciphertext) < ns {
+		return nil, errors.New("ciphertext is too short")
+	}
+	return a.aead.Open(nil, ciphertext[:ns], ciphertext[ns:], nil)
+}
+
+// EncryptValue returns value encrypted by e in form enc:<base64>.
+func EncryptValue(e Encrypter, plain string) (string, error) {
+	buf, err := e.Encrypt([]byte(plain))
+	if err != nil {
+		return "", err
+	}
+	return EncryptedPrefix + base64.StdEncoding.EncodeToString(buf), nil
+}
+
+// DecryptValue returns plain text of value in form enc:<base64>.
+// Values without prefix are returned as is.
+func DecryptValue(d Decrypter, s string) (string, error) {
+	if !strings.HasPrefix(s, EncryptedPrefix) {
+		return s, nil
+	}
+
+	buf, err := base64.StdEncoding.DecodeString(s[len(EncryptedPrefix):])
+	if err != nil {
+		return "", fmt.Errorf("decrypt: %w", err)
+	}
+
+	plain, err := d.Decrypt(buf)
+	if err != nil {
+		return "", fmt.Errorf("decrypt: %w", err)
+	}
+	return string(plain), nil
+}
+
+// WithDecrypter returns source decrypting values in form
+// enc:<base64> by d before they are parsed.
+func WithDecrypter(src ConfigSourcer, d Decrypter) ConfigSourcer {
+	return &decryptingSource{src: src, d: d}
+}
+
+type decryptingSource struct {
+	src ConfigSourcer
+	d   Decrypter
+}
+
+func (s *decryptingSource) ApplyTo(g Configer, ow bool) error {
+	return s.src.ApplyTo(&decryptingConfig{Configer: g, d: s.d}, ow)
+}
+
+// Location implements Locator interface if wrapped source implements it.
+func (s *decryptingSource) Location(code string) string {
+	if l, ok := s.src.(Locator); ok {
+		return l.Location(code)
+	}
+	return ""
+}
+
+// decryptingConfig returns valuers decrypting values before Parse.
+type decryptingConfig struct {
+	Configer
+	d Decrypter
+}
+
+func (c *decryptingConfig) Param(code string, ak AKind) (Valuer, error) {
+	v, err := c.Configer.Param(code, ak)
+	if err != nil {
+		return nil, err
+	}
+	return &decryptingValuer{Valuer: v, d: c.d}, nil
+}
+
+func (c *decryptingConfig) MustParam(code string, ak AKind) Valuer {
+	return &decryptingValuer{Valuer: c.Configer.MustParam(code, ak), d: c.d}
+}
+
+func (c *decryptingConfig) Get(code string) (Valuer, bool) {
+	v, ok := c.Configer.Get(code)
+	if !ok {
+		return nil, false
+	}
+	return &decryptingValuer{Valuer: v, d: c.d}, true
+}
+
+type decryptingValuer struct {
+	Valuer
+	d Decrypter
+}
+
+func (v *decryptingValuer) Parse(s string) error {
+	s, err := DecryptValue(v.d, s)
+	if err != nil {
+		return err
+	}
+	return v.Valuer.Parse(s)
+}
@@ -0,0 +1,109 @@
+package gonfig_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/axkit/gonfig"
+	"github.com/axkit/gonfig/gonfigenv"
+	"github.com/axkit/gonfig/gonfigfile"
+)
+
+func TestAESGCM(t *testing.T) {
+
+	if _, err := gonfig.NewAESGCM([]byte("short")); err != gonfig.ErrInvalidKey {
+		t.Errorf("expected ErrInvalidKey, got %v", err)
+	}
+
+	key, err := gonfig.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(t.TempDir(), "key")
+	os.WriteFile(path, []byte(key+"\n"), 0600)
+
+	a, err := gonfig.LoadAESGCM(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	enc, err := gonfig.EncryptValue(a, "qwerty")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(enc, gonfig.EncryptedPrefix) || strings.Contains(enc, "qwerty") {
+		t.Errorf("unexpected encrypted value %s", enc)
+	}
+
+	plain, err := gonfig.DecryptValue(a, enc)
+	if err != nil || plain != "qwerty" {
+		t.Errorf("DecryptValue() failed: %s, %v", plain, err)
+	}
+
+	if plain, _ := gonfig.DecryptValue(a, "plain"); plain != "plain" {
+		t.Error("value without prefix must be returned as is")
+	}
+
+	if _, err := gonfig.DecryptValue(a, enc[:len(enc)-4]+"AAAA"); err == nil {
+		t.Error("error expected for corrupted value")
+	}
+}
+
+func TestLoader_WithDecrypter(t *testing.T) {
+
+	key, _ := gonfig.GenerateKey()
+	keyPath := filepath.Join(t.TempDir(), "key")
+	os.WriteFile(keyPath, []byte(key), 0600)
+	a, err := gonfig.LoadAESGCM(keyPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dbPassword, _ := gonfig.EncryptValue(a, "db-secret")
+	apiToken, _ := gonfig.EncryptValue(a, "api-secret")
+
+	path := filepath.Join(t.TempDir(), "app.yaml")
+	os.WriteFile(path, []byte("db_password: "+dbPassword+"\nhost: localhost\n"), 0600)
+
+	os.Setenv("DECTEST_API_TOKEN", apiToken)
+	defer os.Unsetenv("DECTEST_API_TOKEN")
+
+	type store struct {
+		DBPassword gonfig.Secret `cfg:"db_password"`
+		APIToken   gonfig.Secret `cfg:"api_token"`
+		Host       gonfig.String `cfg:"host"`
+	}
+
+	var s store
+	cfg := gonfig.New()
+	cfg.BindStruct(&s)
+
+	err = gonfig.NewLoader().
+		WithDecrypter(a).
+		Add("file", gonfigfile.NewFileSource(path, "")).
+		Add("env", gonfigenv.NewEnvSource("DECTEST_", true)).
+		Load(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if s.DBPassword.Reveal() != "db-secret" || s.APIToken.Reveal() != "api-secret" || s.Host.Val() != "localhost" {
+		t.Errorf("values are not decrypted: %s, %s, %s", s.DBPassword.Reveal(), s.APIToken.Reveal(), s.Host.Val())
+	}
+
+	if o, _ := cfg.Origin("api_token"); o.String() != "env:DECTEST_API_TOKEN" {
+		t.Errorf("unexpected origin %s", o)
+	}
+
+	other, _ := gonfig.NewAESGCM(make([]byte, 32))
+	err = gonfig.NewLoader().
+		WithDecrypter(other).
+		Add("file", gonfigfile.NewFileSource(path, "")).
+		Load(cfg)
+	if err == nil {
+		t.Error("error expected for wrong key")
+	}
+}
@@ -0,0 +1,200 @@
+package gonfig
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"sort"
+	"strings"
+)
+
+// DocFormat represents format of documentation written by Documenter.
+type DocFormat uint8
+
+const (
+	// DocMarkdown writes Markdown tables.
+	DocMarkdown DocFormat = 1
+
+	// DocHTML writes HTML page with tables.
+	DocHTML DocFormat = 2
+
+	// DocMan writes man page in troff format.
+	DocMan DocFormat = 3
+)
+
+// Documenter writes documentation of params: code, kind, default
+// value, description, environment variable and whether param is
+// dynamic. Params are grouped by ParamInfo.Group.
+type Documenter struct {
+	// Format is a format of output.
+	Format DocFormat
+
+	// Title is a title of document. Default is "Configuration".
+	Title string
+
+	// EnvPrefix is prepended to environment variable names.
+	// Names are made by EnvName as ExportEnv does.
+	EnvPrefix string
+}
+
+// docGroup is a group of params in documentation.
+type docGroup struct {
+	Name   string
+	Params []docParam
+}
+
+type docParam struct {
+	Code    string
+	Kind    string
+	Default string
+	Desc    string
+	Env     string
+	Dynamic bool
+}
+
+// Write writes documentation of all params of g to w.
+func (d *Documenter) Write(w io.Writer, g Describer) error {
+	var infos []ParamInfo
+	g.WalkInfo(func(v Valuer, info ParamInfo) {
+		infos = append(infos, info)
+	})
+	return d.WriteInfo(w, infos)
+}
+
+// WriteInfo writes documentation of params described by infos to w.
+func (d *Documenter) WriteInfo(w io.Writer, infos []ParamInfo) error {
+	title := d.Title
+	if title == "" {
+		title = "Configuration"
+	}
+
+	groups := d.groups(infos)
+	switch d.Format {
+	case DocMarkdown:
+		return d.markdown(w, title, groups)
+	case DocHTML:
+		return docPage.Execute(w, struct {
+			Title  string
+			Groups []docGroup
+		}{title, groups})
+	case DocMan:
+		return d.man(w, title, groups)
+	}
+	return fmt.Errorf("unknown doc format %d", d.Format)
+}
+
+// groups sorts params by group and code. Params without group go first.
+func (d *Documenter) groups(infos []ParamInfo) []docGroup {
+	sorted := append([]ParamInfo(nil), infos...)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Group != sorted[j].Group {
+			return sorted[i].Group < sorted[j].Group
+		}
+		return sorted[i].Code < sorted[j].Code
+	})
+
+	var res []docGroup
+	for _, info := range sorted {
+		if len(res) == 0 || res[len(res)-1].Name != info.Group {
+			res = append(res, docGroup{Name: info.Group})
+		}
+
+		p := docParam{
+			Code:    info.Code,
+			Kind:    info.Kind.String(),
+			Default: info.Default,
+			Desc:    info.Desc,
+			Env:     EnvName(d.EnvPrefix, info.Code),
+			Dynamic: !info.Static,
+		}
+		if info.Kind == ASecret {
+			p.Default = mask(p.Default)
+		}
+		if info.Unit != "" {
+			p.Desc = strings.TrimSpace(p.Desc + " (" + info.Unit + ")")
+		}
+
+		g := &res[len(res)-1]
+		g.Params = append(g.Params, p)
+	}
+	return res
+}
+
+func (d *Documenter) markdown(w io.Writer, title string, groups []docGroup) error {
+	cell := strings.NewReplacer("|", `\|`, "\n", " ")
+	yes := map[bool]string{true: "yes", false: "no"}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n", title)
+	for _, g := range groups {
+		if g.Name != "" {
+			fmt.Fprintf(&b, "\n## %s\n", g.Name)
+		}
+		b.WriteString("\n| Code | Kind | Default | Environment | Dynamic | Description |\n")
+		b.WriteString("|------|------|---------|-------------|---------|-------------|\n")
+		for _, p := range g.Params {
+			def := ""
+			if p.Default != "" {
+				def = "`" + cell.Replace(p.Default) + "`"
+			}
+			fmt.Fprintf(&b, "| `%s` | %s | %s | `%s` | %s | %s |\n",
+				cell.Replace(p.Code), p.Kind, def, p.Env, yes[p.Dynamic], cell.Replace(p.Desc))
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func (d *Documenter) man(w io.Writer, title string, groups []docGroup) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, ".TH %s 5\n.SH NAME\n%s \\- configuration parameters\n",
+		troff(strings.ToUpper(strings.ReplaceAll(title, " ", "_"))), troff(title))
+	for _, g := range groups {
+		name := "PARAMETERS"
+		if g.Name != "" {
+			name = strings.ToUpper(g.Name)
+		}
+		fmt.Fprintf(&b, ".SH %s\n", troff(name))
+		for _, p := range g.Params {
+			fmt.Fprintf(&b, ".TP\n.B %s\n", troff(p.Code))
+			if p.Desc != "" {
+				fmt.Fprintf(&b, "%s\n.br\n", troff(p.Desc))
+			}
+			fmt.Fprintf(&b, "Kind: %s", p.Kind)
+			if p.Default != "" {
+				fmt.Fprintf(&b, ", default: %s", troff(p.Default))
+			}
+			fmt.Fprintf(&b, ", environment: %s", troff(p.Env))
+			if !p.Dynamic {
+				b.WriteString(", restart required")
+			}
+			b.WriteString(".\n")
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// troff escapes s for troff input.
+func troff(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\e`)
+	s = strings.ReplaceAll(s, "-", `\-`)
+	if strings.HasPrefix(s, ".") || strings.HasPrefix(s, "'") {
+		s = `\&` + s
+	}
+	return s
+}
+
+var docPage = template.Must(template.New("docs").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>{{.Title}}</title></head>
+<body>
+<h1>{{.Title}}</h1>
+{{range .Groups}}{{if .Name}}<h2>{{.Name}}</h2>
+{{end}}<table border="1" cellpadding="4" cellspacing="0">
+<tr><th>Code</th><th>Kind</th><th>Default</th><th>Environment</th><th>Dynamic</th><th>Description</th></tr>
+{{range .Params}}<tr><td><code>{{.Code}}</code></td><td>{{.Kind}}</td><td>{{.Default}}</td><td><code>{{.Env}}</code></td><td>{{if .Dynamic}}yes{{else}}no{{end}}</td><td>{{.Desc}}</td></tr>
+{{end}}</table>
+{{end}}</body>
+</html>
+`))
@@ -0,0 +1,61 @@
+package gonfig_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/axkit/gonfig"
+)
+
+func TestDocumenter(t *testing.T) {
+
+	var c struct {
+		Pool    gonfig.Int    `cfg:"db.pool" default:"10" desc:"Pool size" group:"db" static:"true"`
+		Pass    gonfig.Secret `cfg:"db.password" default:"qwerty" group:"db"`
+		Timeout gonfig.Int    `cfg:"timeout" default:"500" unit:"ms" desc:"Request | timeout"`
+	}
+	cfg := gonfig.New()
+	cfg.BindStruct(&c)
+
+	tcs := []struct {
+		format gonfig.DocFormat
+		want   []string
+	}{
+		{gonfig.DocMarkdown, []string{
+			"# Settings\n",
+			"| `timeout` | AInt | `500` | `APP_TIMEOUT` | yes | Request \\| timeout (ms) |\n",
+			"\n## db\n",
+			"| `db.pool` | AInt | `10` | `APP_DB__POOL` | no | Pool size |\n",
+			"| `db.password` | ASecret | `******` | `APP_DB__PASSWORD` | yes |  |\n",
+		}},
+		{gonfig.DocHTML, []string{
+			"<title>Settings</title>",
+			"<h2>db</h2>",
+			"<td><code>db.pool</code></td><td>AInt</td><td>10</td><td><code>APP_DB__POOL</code></td><td>no</td><td>Pool size</td>",
+			"Request | timeout (ms)",
+		}},
+		{gonfig.DocMan, []string{
+			".TH SETTINGS 5\n",
+			".SH DB\n.TP\n.B db.password\n",
+			".TP\n.B db.pool\nPool size\n.br\nKind: AInt, default: 10, environment: APP_DB__POOL, restart required.\n",
+			".SH PARAMETERS\n.TP\n.B timeout\n",
+		}},
+	}
+
+	for _, tc := range tcs {
+		var buf bytes.Buffer
+		d := gonfig.Documenter{Format: tc.format, Title: "Settings", EnvPrefix: "APP_"}
+		if err := d.Write(&buf, cfg); err != nil {
+			t.Fatal(err)
+		}
+		for _, w := range tc.want {
+			if !strings.Contains(buf.String(), w) {
+				t.Errorf("format %d: expected %q in\n%s", tc.format, w, buf.String())
+			}
+		}
+		if strings.Contains(buf.String(), "qwerty") {
+			t.Errorf("format %d: secret revealed", tc.format)
+		}
+	}
+}
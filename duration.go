@@ -0,0 +1,160 @@
+package gonfig
+
+import (
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+	"unsafe"
+)
+
+// NonBindedDuration is returned by method Val
+// if Duration is not initialized yet.
+var NonBindedDuration time.Duration = 0
+
+// A Duration implements Valuer as atomic time.Duration,
+// implemented using int64 inside.
+type Duration struct {
+	ref *int64
+
+	// c is container the param belongs to.
+	c *Config
+}
+
+// NewDuration returns atomic duration.
+func NewDuration() *Duration {
+	return &Duration{ref: new(int64)}
+}
+
+// Kind returns ADuration.
+func (a *Duration) Kind() AKind {
+	return ADuration
+}
+
+// Set assigns value atomically. Initializes if was not before.
+func (a *Duration) Set(d time.Duration) {
+	if a.owner().rejected(a.mem(), func() Valuer {
+		n := NewDuration()
+		n.Set(d)
+		return n
+	}) {
+		return
+	}
+	a.set(d, nil)
+}
+
+// set is like Set, the change is attributed to by.
+func (a *Duration) set(d time.Duration, by *author) {
+	ptr := atomic.LoadPointer((*unsafe.Pointer)(unsafe.Pointer(&a.ref)))
+	if n := a.owner().notifierOf(ptr); n != nil {
+		n.change(by, func() (string, string, bool) {
+			old := time.Duration(atomic.SwapInt64((*int64)(ptr), int64(d)))
+			return old.String(), d.String(), old != d
+		})
+		return
+	}
+	if ptr != nil {
+		atomic.StoreInt64((*int64)(ptr), int64(d))
+		return
+	}
+
+	n := NewDuration()
+	a.Bind(n)
+	atomic.StoreInt64(n.ref, int64(d))
+}
+
+// Val returns value atomically. Returns NonBindedDuration
+// if it's not binded to params container.
+func (a *Duration) Val() time.Duration {
+	ptr := atomic.LoadPointer((*unsafe.Pointer)(unsafe.Pointer(&a.ref)))
+	if ptr == nil {
+		return NonBindedDuration
+	}
+	return time.Duration(atomic.LoadInt64((*int64)(ptr)))
+}
+
+// IsBinded returns true if Duration bineded to params container.
+func (a *Duration) IsBinded() bool {
+	return atomic.LoadPointer((*unsafe.Pointer)(unsafe.Pointer(&a.ref))) != nil
+}
+
+func (a *Duration) mem() unsafe.Pointer {
+	return atomic.LoadPointer((*unsafe.Pointer)(unsafe.Pointer(&a.ref)))
+}
+
+func (a *Duration) owner() *Config {
+	return loadOwner(&a.c)
+}
+
+func (a *Duration) own(c *Config) {
+	storeOwner(&a.c, c)
+}
+
+func (a *Duration) assign(v Valuer, by *author) {
+	a.set(v.(*Duration).Val(), by)
+}
+
+// Bind binds current atomic variable to variable identified by to.
+// As a result two Duration address to the same variable.
+func (a *Duration) Bind(to *Duration) {
+	ptr := atomic.LoadPointer((*unsafe.Pointer)(unsafe.Pointer(&to.ref)))
+	if ptr != nil {
+		storeOwner(&a.c, to.owner())
+		atomic.StorePointer((*unsafe.Pointer)(unsafe.Pointer(&a.ref)), ptr)
+	}
+}
+
+// String implements Stringer interface. Returns value
+// formatted by time.Duration.String, e.g. "1m30s".
+func (a *Duration) String() string {
+	return a.Val().String()
+}
+
+// Parse converts input argument and assigns to value.
+// Accepts strings accepted by time.ParseDuration.
+// Value is not changed if it violates validation rules.
+func (a *Duration) Parse(s string) error {
+	s = strings.TrimSpace(s)
+	var d time.Duration
+	if len(s) != 0 {
+		var err error
+		if d, err = time.ParseDuration(s); err != nil {
+			return err
+		}
+	}
+
+	if vr := a.owner().validatorOf(a.mem()); vr != nil {
+		n := NewDuration()
+		n.Set(d)
+		if err := vr.check(n); err != nil {
+			return err
+		}
+	}
+	a.Set(d)
+	return nil
+}
+
+// MarshalJSON implement Marshaller interface.
+func (a Duration) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Quote(a.String())), nil
+}
+
+// UnmarshalJSON implement Unmarshaller interface. Accepts
+// quoted duration string or number of nanoseconds.
+func (a *Duration) UnmarshalJSON(buf []byte) error {
+	s := string(buf)
+	if len(s) >= 2 && s[0] == '"' {
+		v, err := strconv.Unquote(s)
+		if err != nil {
+			return err
+		}
+		return a.Parse(v)
+	}
+
+	i, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return err
+	}
+	a.Set(time.Duration(i))
+	return nil
+}
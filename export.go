@@ -0,0 +1,270 @@
+package gonfig
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ExportFormat represents format of exported params.
+type ExportFormat uint8
+
+const (
+	// ExportJSON writes JSON object, codes are split by separator
+	// into nested objects.
+	ExportJSON ExportFormat = 1
+
+	// ExportJSONFlat writes JSON object with codes as keys.
+	ExportJSONFlat ExportFormat = 2
+
+	// ExportYAML writes YAML document, codes are split by separator
+	// into nested mappings.
+	ExportYAML ExportFormat = 3
+
+	// ExportEnv writes lines NAME="value" accepted by .env files.
+	ExportEnv ExportFormat = 4
+
+	// ExportFlags writes lines --code=value.
+	ExportFlags ExportFormat = 5
+)
+
+// Exporter writes all params of config container. Values of secrets
+// are masked.
+type Exporter struct {
+	// Format is a format of output.
+	Format ExportFormat
+
+	// Separator splits codes into nested keys. Default is ".".
+	Separator string
+
+	// EnvPrefix is prepended to variable names by ExportEnv.
+	EnvPrefix string
+
+	// Comments adds kind and origin of every param as comment.
+	// Ignored by JSON formats.
+	Comments bool
+}
+
+type exported struct {
+	code   string
+	v      Valuer
+	origin string
+}
+
+// Export writes params of g to w. Origins are written if g
+// implements Originer.
+func (e *Exporter) Export(w io.Writer, g Snapshotter) error {
+	s := g.Snapshot()
+	og, _ := g.(Originer)
+	codes := s.Codes()
+	sort.Strings(codes)
+
+	params := make([]exported, len(codes))
+	for i, code := range codes {
+		v, _ := s.Get(code)
+		params[i] = exported{code: code, v: v}
+		if og == nil {
+			continue
+		}
+		if o, ok := og.Origin(code); ok {
+			params[i].origin = o.String()
+		}
+	}
+
+	sep := e.Separator
+	if sep == "" {
+		sep = "."
+	}
+
+	switch e.Format {
+	case ExportJSON, ExportJSONFlat:
+		return e.json(w, params, sep)
+	case ExportYAML:
+		return e.yaml(w, params, sep)
+	case ExportEnv, ExportFlags:
+		return e.lines(w, params)
+	}
+	return fmt.Errorf("unknown export format %d", e.Format)
+}
+
+// Export writes all params to w in format f.
+func (c *Config) Export(w io.Writer, f ExportFormat) error {
+	return (&Exporter{Format: f}).Export(w, c)
+}
+
+func (e *Exporter) json(w io.Writer, params []exported, sep string) error {
+	root := make(map[string]interface{})
+	for _, p := range params {
+		if e.Format == ExportJSONFlat {
+			root[p.code] = typed(p.v)
+			continue
+		}
+
+		m := root
+		keys := strings.Split(p.code, sep)
+		for _, k := range keys[:len(keys)-1] {
+			next, ok := m[k].(map[string]interface{})
+			if !ok {
+				if _, exists := m[k]; exists {
+					return fmt.Errorf("param '%s' conflicts with param '%s'", p.code, k)
+				}
+				next = make(map[string]interface{})
+				m[k] = next
+			}
+			m = next
+		}
+
+		k := keys[len(keys)-1]
+		if _, exists := m[k]; exists {
+			return fmt.Errorf("param '%s' conflicts with nested params", p.code)
+		}
+		m[k] = typed(p.v)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(root)
+}
+
+func (e *Exporter) yaml(w io.Writer, params []exported, sep string) error {
+	root := &yaml.Node{Kind: yaml.MappingNode}
+
+	for _, p := range params {
+		m := root
+		keys := strings.Split(p.code, sep)
+		for _, k := range keys[:len(keys)-1] {
+			next := child(m, k)
+			if next == nil {
+				next = &yaml.Node{Kind: yaml.MappingNode}
+				m.Content = append(m.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: k}, next)
+			} else if next.Kind != yaml.MappingNode {
+				return fmt.Errorf("param '%s' conflicts with param '%s'", p.code, k)
+			}
+			m = next
+		}
+
+		k := keys[len(keys)-1]
+		if child(m, k) != nil {
+			return fmt.Errorf("param '%s' conflicts with nested params", p.code)
+		}
+
+		var vn yaml.Node
+		if err := vn.Encode(typed(p.v)); err != nil {
+			return err
+		}
+		kn := &yaml.Node{Kind: yaml.ScalarNode, Value: k}
+		if e.Comments {
+			kn.LineComment = e.comment(p)
+		}
+		m.Content = append(m.Content, kn, &vn)
+	}
+
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(&yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{root}}); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+// child returns value node of mapping m identified by key k.
+func child(m *yaml.Node, k string) *yaml.Node {
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == k {
+			return m.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// nonEnv matches characters not allowed in names of environment
+// variables. Placeholder * of slice index or map key is kept.
+var nonEnv = regexp.MustCompile(`[^A-Za-z0-9_*]`)
+
+// EnvName returns name of environment variable for param identified
+// by code. Letters are upper cased, dots are replaced by double
+// underscore and other characters except digits and underscore by
+// underscore. EnvCode restores codes of letters, digits, underscores
+// and dots, as gonfigenv.EnvSource does.
+func EnvName(prefix, code string) string {
+	return prefix + strings.ToUpper(nonEnv.ReplaceAllString(strings.ReplaceAll(code, ".", "__"), "_"))
+}
+
+// EnvCode returns code of param from name of environment variable
+// without prefix, double underscores are replaced by dots. It
+// reverses EnvName except case of letters.
+func EnvCode(name string) string {
+	return strings.ReplaceAll(name, "__", ".")
+}
+
+func (e *Exporter) lines(w io.Writer, params []exported) error {
+	for _, p := range params {
+		if e.Comments {
+			if _, err := fmt.Fprintln(w, "#", e.comment(p)); err != nil {
+				return err
+			}
+		}
+
+		val := text(p.v)
+		var err error
+		if e.Format == ExportEnv {
+			name := EnvName(e.EnvPrefix, p.code)
+			_, err = fmt.Fprintf(w, "%s=%s\n", name, strconv.Quote(val))
+		} else {
+			_, err = fmt.Fprintf(w, "--%s=%s\n", p.code, shellQuote(val))
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *Exporter) comment(p exported) string {
+	if p.origin == "" {
+		return p.v.Kind().String()
+	}
+	return p.v.Kind().String() + ", " + p.origin
+}
+
+// typed returns value suitable for JSON and YAML encoding.
+func typed(v Valuer) interface{} {
+	switch x := v.(type) {
+	case *Int:
+		return x.Val()
+	case *Bool:
+		return x.Val()
+	case *Float:
+		return x.Val()
+	case *StringSlice:
+		return x.Val()
+	case *IntSlice:
+		return x.Val()
+	case *Secret:
+		return x.String()
+	}
+	return text(v)
+}
+
+// text returns value as a string. Floats are not rounded,
+// secrets are masked.
+func text(v Valuer) string {
+	if f, ok := v.(*Float); ok {
+		return formatFloat(f.Val())
+	}
+	return fmt.Sprint(v)
+}
+
+// shellQuote quotes s for POSIX shell if needed.
+func shellQuote(s string) string {
+	if s != "" && !strings.ContainsAny(s, " \t\n'\"\\$`!*?[]{}()<>|&;#~") {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,137 @@
+package gonfig_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/axkit/gonfig"
+)
+
+func exportConfig(t *testing.T) *gonfig.Config {
+	t.Helper()
+
+	type C struct {
+		Host  gonfig.String      `cfg:"db.host" default:"localhost"`
+		Port  gonfig.Int         `cfg:"db.port" default:"5432"`
+		Pass  gonfig.Secret      `cfg:"db.password" default:"qwerty"`
+		Ratio gonfig.Float       `cfg:"ratio" default:"0.125"`
+		Hosts gonfig.StringSlice `cfg:"hosts" default:"a,b"`
+		Debug gonfig.Bool        `cfg:"debug" default:"true"`
+		Title gonfig.String      `cfg:"title" default:"it's me"`
+	}
+
+	var c C
+	cfg := gonfig.New()
+	if errs := cfg.BindStruct(&c); len(errs) > 0 {
+		t.Fatal(errs)
+	}
+	cfg.SetOrigin("db.port", gonfig.Origin{Source: "env", Location: "DB_PORT"})
+	return cfg
+}
+
+func TestConfig_Export(t *testing.T) {
+
+	cfg := exportConfig(t)
+
+	var buf bytes.Buffer
+	if err := cfg.Export(&buf, gonfig.ExportJSON); err != nil {
+		t.Fatal(err)
+	}
+
+	var nested struct {
+		DB struct {
+			Host     string `json:"host"`
+			Port     int    `json:"port"`
+			Password string `json:"password"`
+		} `json:"db"`
+		Ratio float64  `json:"ratio"`
+		Hosts []string `json:"hosts"`
+		Debug bool     `json:"debug"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &nested); err != nil {
+		t.Fatal(err, buf.String())
+	}
+	if nested.DB.Host != "localhost" || nested.DB.Port != 5432 || nested.Ratio != 0.125 ||
+		len(nested.Hosts) != 2 || !nested.Debug {
+		t.Errorf("unexpected nested JSON %s", buf.String())
+	}
+	if nested.DB.Password != gonfig.SecretMask {
+		t.Errorf("secret is not masked: %s", buf.String())
+	}
+
+	buf.Reset()
+	if err := cfg.Export(&buf, gonfig.ExportJSONFlat); err != nil {
+		t.Fatal(err)
+	}
+	flat := map[string]interface{}{}
+	if err := json.Unmarshal(buf.Bytes(), &flat); err != nil {
+		t.Fatal(err)
+	}
+	if flat["db.port"] != float64(5432) || flat["db.password"] != gonfig.SecretMask {
+		t.Errorf("unexpected flat JSON %s", buf.String())
+	}
+}
+
+func TestExporter_Text(t *testing.T) {
+
+	cfg := exportConfig(t)
+
+	tcs := []struct {
+		name string
+		e    gonfig.Exporter
+		want []string
+	}{
+		{"yaml", gonfig.Exporter{Format: gonfig.ExportYAML, Comments: true}, []string{
+			"db:\n  host: localhost # AString\n",
+			"  password: '******' # ASecret\n",
+			"  port: 5432 # AInt, env:DB_PORT\n",
+			"ratio: 0.125 # AFloat\n",
+			"hosts:",
+		}},
+		{"env", gonfig.Exporter{Format: gonfig.ExportEnv, EnvPrefix: "APP_"}, []string{
+			"APP_DB__HOST=\"localhost\"\n",
+			"APP_DB__PASSWORD=\"******\"\n",
+			"APP_RATIO=\"0.125\"\n",
+			"APP_HOSTS=\"a,b\"\n",
+		}},
+		{"flags", gonfig.Exporter{Format: gonfig.ExportFlags, Comments: true}, []string{
+			"# AInt, env:DB_PORT\n--db.port=5432\n",
+			"--db.password='******'\n",
+			"--title='it'\\''s me'\n",
+			"--debug=true\n",
+		}},
+	}
+
+	for _, tc := range tcs {
+		var buf bytes.Buffer
+		if err := tc.e.Export(&buf, cfg); err != nil {
+			t.Errorf("%s: %v", tc.name, err)
+			continue
+		}
+		for _, w := range tc.want {
+			if !strings.Contains(buf.String(), w) {
+				t.Errorf("%s: expected %q in\n%s", tc.name, w, buf.String())
+			}
+		}
+		if strings.Contains(buf.String(), "qwerty") {
+			t.Errorf("%s: secret revealed", tc.name)
+		}
+	}
+}
+
+func TestExporter_Conflict(t *testing.T) {
+
+	cfg := gonfig.New()
+	cfg.Param("db", gonfig.AString)
+	cfg.Param("db.host", gonfig.AString)
+
+	var buf bytes.Buffer
+	if err := cfg.Export(&buf, gonfig.ExportJSON); err == nil {
+		t.Error("expected error")
+	}
+	if err := cfg.Export(&buf, gonfig.ExportJSONFlat); err != nil {
+		t.Error(err)
+	}
+}
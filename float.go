@@ -22,11 +22,14 @@ var (
 // implemented using uint64 inside.
 type Float struct {
 	ref *uint64
+
+	// c is container the param belongs to.
+	c *Config
 }
 
 // NewFloat returns atomic float.
 func NewFloat() *Float {
-	return &Float{new(uint64)}
+	return &Float{ref: new(uint64)}
 }
 
 // Kind returns AFloat.
@@ -35,9 +38,29 @@ func (a *Float) Kind() AKind {
 }
 
 // Set assigns value atomically.Initializes if was not before.
+// OnChange subscribers receive values formatted without rounding.
 func (a *Float) Set(f float64) {
+	if a.owner().rejected(a.mem(), func() Valuer {
+		n := NewFloat()
+		n.Set(f)
+		return n
+	}) {
+		return
+	}
+	a.set(f, nil)
+}
+
+// set is like Set, the change is attributed to by.
+func (a *Float) set(f float64, by *author) {
 	fu := math.Float64bits(f)
 	ptr := atomic.LoadPointer((*unsafe.Pointer)(unsafe.Pointer(&a.ref)))
+	if n := a.owner().notifierOf(ptr); n != nil {
+		n.change(by, func() (string, string, bool) {
+			old := atomic.SwapUint64((*uint64)(ptr), fu)
+			return formatFloat(math.Float64frombits(old)), formatFloat(f), old != fu
+		})
+		return
+	}
 	if ptr != nil {
 		atomic.StoreUint64((*uint64)(ptr), fu)
 		return
@@ -63,11 +86,28 @@ func (a *Float) IsBinded() bool {
 	return atomic.LoadPointer((*unsafe.Pointer)(unsafe.Pointer(&a.ref))) != nil
 }
 
+func (a *Float) mem() unsafe.Pointer {
+	return atomic.LoadPointer((*unsafe.Pointer)(unsafe.Pointer(&a.ref)))
+}
+
+func (a *Float) owner() *Config {
+	return loadOwner(&a.c)
+}
+
+func (a *Float) own(c *Config) {
+	storeOwner(&a.c, c)
+}
+
+func (a *Float) assign(v Valuer, by *author) {
+	a.set(v.(*Float).Val(), by)
+}
+
 // Bind binds current atomic variable to variable identified by to.
 // As a result two Float address to the same variable.
 func (a *Float) Bind(to *Float) {
 	ptr := atomic.LoadPointer((*unsafe.Pointer)(unsafe.Pointer(&to.ref)))
 	if ptr != nil {
+		storeOwner(&a.c, to.owner())
 		atomic.StorePointer((*unsafe.Pointer)(unsafe.Pointer(&a.ref)), ptr)
 	}
 }
@@ -79,16 +119,23 @@ func (a *Float) String() string {
 }
 
 // Parse converts input argument and assigns to value.
+// Value is not changed if it violates validation rules.
 func (a *Float) Parse(s string) error {
 	s = strings.TrimSpace(s)
-	if len(s) == 0 {
-		a.Set(0.0)
-		return nil
+	var f float64
+	if len(s) != 0 {
+		var err error
+		if f, err = strconv.ParseFloat(s, 64); err != nil {
+			return err
+		}
 	}
 
-	f, err := strconv.ParseFloat(s, 64)
-	if err != nil {
-		return err
+	if vr := a.owner().validatorOf(a.mem()); vr != nil {
+		n := NewFloat()
+		n.Set(f)
+		if err := vr.check(n); err != nil {
+			return err
+		}
 	}
 	a.Set(f)
 	return nil
@@ -103,3 +150,8 @@ func (a Float) MarshalJSON() ([]byte, error) {
 func (a *Float) UnmarshalJSON(buf []byte) error {
 	return a.Parse(string(buf))
 }
+
+// formatFloat formats f without rounding.
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
@@ -1,6 +1,7 @@
 package gonfig
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"reflect"
@@ -8,7 +9,7 @@ import (
 )
 
 // AKind represents possible kinds of config param data type.
-// The zero kind is not a valid kind.
+// The zero kind is not a valid kind. Custom kinds are added by RegisterKind.
 type AKind uint8
 
 const (
@@ -26,8 +27,32 @@ const (
 
 	// AFloat represents atomic float. It's float64 internally.
 	AFloat AKind = 4
+
+	// ADuration represents atomic time.Duration. It's int64 internally.
+	ADuration AKind = 5
+
+	// AStringSlice represents atomic slice of strings.
+	AStringSlice AKind = 6
+
+	// AIntSlice represents atomic slice of ints.
+	AIntSlice AKind = 7
+
+	// ASecret represents atomic string which value is masked.
+	ASecret AKind = 8
+
+	// lastKind is the last built-in kind.
+	lastKind = ASecret
 )
 
+// isValid returns true if ak is built-in or registered kind.
+func (ak AKind) isValid() bool {
+	if ak > Unknown && ak <= lastKind {
+		return true
+	}
+	_, ok := kindByCode(ak)
+	return ok
+}
+
 type action uint8
 
 const (
@@ -46,6 +71,17 @@ func (ak AKind) String() string {
 		return "AString"
 	case AFloat:
 		return "AFloat"
+	case ADuration:
+		return "ADuration"
+	case AStringSlice:
+		return "AStringSlice"
+	case AIntSlice:
+		return "AIntSlice"
+	case ASecret:
+		return "ASecret"
+	}
+	if d, ok := kindByCode(ak); ok {
+		return d.name
 	}
 	return "Unknown"
 }
@@ -78,6 +114,39 @@ type Configer interface {
 	Walk(func(code string, v Valuer, inited, asked int))
 }
 
+// Following interfaces are implemented by Config and views returned
+// by Sub. Packages accepting Configer check them by type assertion.
+
+// An Originer keeps origins of param values.
+type Originer interface {
+	Origin(code string) (Origin, bool)
+	SetOrigin(code string, o Origin)
+}
+
+// An Updater changes params attributing changes to actor and source
+// carried by context.
+type Updater interface {
+	Update(f func(tx Tx) error) error
+	UpdateContext(ctx context.Context, f func(tx Tx) error) error
+	ParseContext(ctx context.Context, code, s string) error
+}
+
+// A Snapshotter returns consistent copy of param values.
+type Snapshotter interface {
+	Snapshot(codes ...string) *Snapshot
+}
+
+// A Describer returns descriptions of params.
+type Describer interface {
+	Info(code string) (ParamInfo, bool)
+	WalkInfo(f func(v Valuer, info ParamInfo))
+}
+
+// A FieldBinder binds a single var as BindStruct binds field with tag.
+type FieldBinder interface {
+	BindField(code string, v Valuer, tag reflect.StructTag) []error
+}
+
 // Valuer is an interface what wraps following methods.
 //
 // Kind returns data type of Valuer.
@@ -93,10 +162,25 @@ type Valuer interface {
 	IsBinded() bool
 }
 
+// separatorSetter is implemented by slice valuers.
+type separatorSetter interface {
+	SetSeparator(sep string)
+}
+
+// binder is implemented by valuers of registered kinds.
+// bindValuer binds valuer to v if it's not binded yet.
+// Returns false if v has different type.
+type binder interface {
+	bindValuer(v Valuer) bool
+}
+
 // ConfigSourcer is an interface wrapping a single method ApplyTo.
 //
 // ApplyTo reads parameters from the source: database, file, env, etc.
 // and adds them into config param container. Value overwrites if overwrite is true.
+//
+// Loader records origin of values assigned by Parse of valuers
+// returned by Param, MustParam or Get of g.
 type ConfigSourcer interface {
 	ApplyTo(g Configer, overwrite bool) error
 }
@@ -106,6 +190,8 @@ type param struct {
 	av     Valuer
 	inited int
 	asked  int
+	origin *Origin
+	meta   meta
 }
 
 // Config is in-memory config params container.
@@ -115,11 +201,48 @@ type Config struct {
 	mux  sync.RWMutex
 	list []param
 	idx  map[string]int
+
+	// txmux makes values published by Update consistent for Snapshot.
+	txmux sync.RWMutex
+
+	// byCode maps code to Valuer. It's used by expanders
+	// which can't lock mux.
+	byCode sync.Map
+
+	// interp is set by WithInterpolation.
+	interp bool
+
+	// hist records changes if turned on by WithHistory.
+	hist *history
+
+	// audit sends changes to sinks added by WithAudit.
+	audit *auditor
+
+	// frozen is set by Freeze.
+	frozen bool
+
+	// psep joins prefixes and codes, set by WithPrefixSeparator.
+	psep string
+
+	// held are notifiers of params changed with locked mux,
+	// their changes are delivered by unlock.
+	held []*notifier
+
+	// notifiers, validators and expanders map shared memory
+	// address of param to *notifier, *validator and *expander.
+	// Valuers find them by owner. Counters are numbers of entries,
+	// lookups are skipped if zero.
+	notifiers  sync.Map
+	validators sync.Map
+	expanders  sync.Map
+	watched    int32
+	validated  int32
+	expanded   int32
 }
 
 // New returns new container of config parameters.
 // It's ok to have a single instance for the whole application.
-func New() Configer {
+func New() *Config {
 	return &Config{idx: make(map[string]int)}
 }
 
@@ -150,8 +273,19 @@ func (c *Config) BindVar(code string, addr Valuer) error {
 		addr.(*String).Bind(p.(*String))
 	case AFloat:
 		addr.(*Float).Bind(p.(*Float))
+	case ADuration:
+		addr.(*Duration).Bind(p.(*Duration))
+	case AStringSlice:
+		addr.(*StringSlice).Bind(p.(*StringSlice))
+	case AIntSlice:
+		addr.(*IntSlice).Bind(p.(*IntSlice))
+	case ASecret:
+		addr.(*Secret).Bind(p.(*Secret))
 	default:
-		return ErrDifferentKind
+		b, ok := addr.(binder)
+		if !ok || !b.bindValuer(p) {
+			return ErrDifferentKind
+		}
 	}
 
 	c.setStat(code, asked)
@@ -169,15 +303,58 @@ func (c *Config) BindVar(code string, addr Valuer) error {
 //		Port gonfig.Int `cfg:"port"`
 // }
 //
+// Slice fields accept tag "sep" overriding DefaultSliceSeparator.
+//
+// Validation rules are declared by tags:
+//
+//	required:"true"       value must be provided by source or default
+//	min:"1" max:"65535"   bounds of numbers, durations or length
+//	                      of strings and slices
+//	oneof:"debug|info"    allowed values
+//	pattern:"^https://"   regular expression value must match
+//
+// Rules are checked at bind time and by every subsequent Parse,
+// which rejects invalid value keeping the old one.
+//
+// Metadata returned by Info is declared by tags:
+//
+//	desc:"Pool size"      description
+//	unit:"ms"             unit of value
+//	group:"db"            group of params
+//	static:"true"         param can't be parsed after Freeze
+//
+// Fields of nested structs are bound to codes with prefix if
+// tag "cfg" has option prefix. Prefix and code are joined by
+// separator set by WithPrefixSeparator, DefaultPrefixSeparator
+// by default. Slices and maps of structs are bound by index or key.
+//
+//	type Config struct {
+//		Admin   Listener            `cfg:"admin,prefix"`   // admin.port
+//		Servers []Server            `cfg:"servers,prefix"` // servers.0.port
+//		Regions map[string]Server   `cfg:"regions,prefix"` // regions.eu.port
+//	}
+//
+// Slices and maps are extended by indexes and keys found in
+// container. Slice indexes must be consecutive, indexes after
+// a gap are reported as errors and not bound.
+//
+// Fields of plain types int, uint, bool, float64, string,
+// time.Duration, slices of them and types implementing
+// encoding.TextUnmarshaler are populated once at bind time, later
+// changes don't reach the field. Param created by such field is
+// static unless tag has key static. Existing param, for example
+// bound to Valuer field as well, keeps its static flag.
+// Unparsable values are reported as errors.
+//
 // BindStruct works properly with fields as structs and
 // embedded anonymous structs.
 func (c *Config) BindStruct(structAddr interface{}) []error {
 	c.mux.Lock()
-	defer c.mux.Unlock()
-	return c.bindStruct(structAddr)
+	defer c.unlock()
+	return c.bindStruct("", structAddr)
 }
 
-func (c *Config) bindStruct(structAddr interface{}) []error {
+func (c *Config) bindStruct(prefix string, structAddr interface{}) []error {
 
 	var res []error
 	s := reflect.ValueOf(structAddr).Elem()
@@ -192,60 +369,131 @@ func (c *Config) bindStruct(structAddr interface{}) []error {
 
 	for i := 0; i < s.NumField(); i++ {
 		f := tof.Field(i)
+		code, isPrefix := parseTag(f.Tag.Get("cfg"))
 
 		if s.Field(i).Addr().Type().Implements(atype) {
-			code := tof.Field(i).Tag.Get("cfg")
 			if code == "" {
 				continue
 			}
-			fai := s.Field(i).Addr().Interface()
-
-			// assigns default value if parametr was not initialized before.
-			def := tof.Field(i).Tag.Get("default")
-			_, wasinit := c.idx[code]
-
-			p, err := c.param(code, fai.(Valuer).Kind(), false)
-			if err != nil {
-				res = append(res, err)
-				continue
-			}
+			res = append(res, c.bindField(prefix+code, s.Field(i).Addr().Interface().(Valuer), f.Tag)...)
+			continue
+		}
 
-			if !wasinit && def != "" {
-				if err := p.Parse(def); err != nil {
-					res = append(res, err)
-				}
-			}
+		if isPrefix {
+			res = append(res, c.bindNested(prefix+code+c.prefixSep(), f.Name, s.Field(i))...)
+			continue
+		}
 
-			switch p.Kind() {
-			case ABool:
-				if a := fai.(*Bool); !a.IsBinded() {
-					a.Bind(p.(*Bool))
-				}
-			case AString:
-				if a := fai.(*String); !a.IsBinded() {
-					a.Bind(p.(*String))
-				}
-			case AInt:
-				if a := fai.(*Int); !a.IsBinded() {
-					a.Bind(p.(*Int))
-				}
-			case AFloat:
-				if a := fai.(*Float); !a.IsBinded() {
-					a.Bind(p.(*Float))
-				}
-			}
-			c.setStat(code, asked)
+		if code != "" && plainKind(f.Type) != Unknown {
+			res = append(res, c.bindPlain(prefix+code, f.Name, s.Field(i), f.Tag)...)
 			continue
 		}
 
 		if f.Anonymous || s.Field(i).Kind() == reflect.Struct {
 			if f.Type.Kind() != reflect.Ptr {
-				c.bindStruct(s.Field(i).Addr().Interface())
+				res = append(res, c.bindStruct(prefix, s.Field(i).Addr().Interface())...)
 			} else {
-				c.bindStruct(s.Field(i).Interface())
+				res = append(res, c.bindStruct(prefix, s.Field(i).Interface())...)
 			}
+			continue
+		}
+
+		if code != "" {
+			res = append(res, c.bindPlain(prefix+code, f.Name, s.Field(i), f.Tag)...)
+		}
+	}
+	return res
+}
+
+// BindField binds v to param identified by code as BindStruct
+// binds struct field having tag. Tag "cfg" is ignored.
+func (c *Config) BindField(code string, v Valuer, tag reflect.StructTag) []error {
+	c.mux.Lock()
+	defer c.unlock()
+	return c.bindField(code, v, tag)
+}
+
+func (c *Config) bindField(code string, v Valuer, tag reflect.StructTag) []error {
+	var res []error
+
+	// assigns default value if parametr was not initialized before.
+	def := tag.Get("default")
+	_, wasinit := c.idx[code]
+
+	p, err := c.param(code, v.Kind(), false)
+	if err != nil {
+		return append(res, err)
+	}
+
+	// separator of slice params, tag "sep".
+	if sep := tag.Get("sep"); sep != "" {
+		if ss, ok := p.(separatorSetter); ok {
+			ss.SetSeparator(sep)
+		}
+	}
+
+	if !wasinit && def != "" {
+		c.hold(p)
+		if err := p.Parse(def); err != nil {
+			res = append(res, err)
 		}
 	}
+
+	// validation rules are checked by every subsequent Parse.
+	rules, err := c.addRules(code, p, tag)
+	if err != nil {
+		res = append(res, err)
+	} else if len(rules) > 0 {
+		if hasRule(rules, "required") && !wasinit && def == "" {
+			res = append(res, &ValidationError{Code: code, Rule: "required"})
+		} else if err := c.validate(p.(watchable).mem(), p); err != nil {
+			res = append(res, err)
+		}
+	}
+
+	if err := c.setMeta(c.idx[code], def, tag.Get("desc"), tag.Get("unit"), tag.Get("group"), tag.Get("static")); err != nil {
+		res = append(res, err)
+	}
+
+	switch p.Kind() {
+	case ABool:
+		if a := v.(*Bool); !a.IsBinded() {
+			a.Bind(p.(*Bool))
+		}
+	case AString:
+		if a := v.(*String); !a.IsBinded() {
+			a.Bind(p.(*String))
+		}
+	case AInt:
+		if a := v.(*Int); !a.IsBinded() {
+			a.Bind(p.(*Int))
+		}
+	case AFloat:
+		if a := v.(*Float); !a.IsBinded() {
+			a.Bind(p.(*Float))
+		}
+	case ADuration:
+		if a := v.(*Duration); !a.IsBinded() {
+			a.Bind(p.(*Duration))
+		}
+	case AStringSlice:
+		if a := v.(*StringSlice); !a.IsBinded() {
+			a.Bind(p.(*StringSlice))
+		}
+	case AIntSlice:
+		if a := v.(*IntSlice); !a.IsBinded() {
+			a.Bind(p.(*IntSlice))
+		}
+	case ASecret:
+		if a := v.(*Secret); !a.IsBinded() {
+			a.Bind(p.(*Secret))
+		}
+	default:
+		if b, ok := v.(binder); ok {
+			b.bindValuer(p)
+		}
+	}
+	c.setStat(code, asked)
 	return res
 }
 
@@ -302,10 +550,35 @@ func (c *Config) param(code string, ak AKind, dopanic bool) (Valuer, error) {
 		return nil, errors.New(msg)
 	}
 
-	p := param{code: code, av: makeValuer(ak)}
-	c.list = append(c.list, p)
+	if !ak.isValid() {
+		if dopanic {
+			panic(ErrUnknownKind.Error() + " " + ak.String())
+		}
+		return nil, ErrUnknownKind
+	}
+
+	v := makeValuer(ak)
+	c.add(code, v)
+	return v, nil
+}
+
+// add adds new param having value v into container.
+// Must be called with locked c.mux.
+func (c *Config) add(code string, v Valuer) {
+	v.(watchable).own(c)
+	c.list = append(c.list, param{code: code, av: v})
 	c.idx[code] = len(c.list) - 1
-	return p.av, nil
+
+	if ak := v.Kind(); c.interp && (ak == AString || ak == ASecret) {
+		c.addExpander(code, v)
+	}
+	c.byCode.Store(code, v)
+	if c.hist != nil {
+		c.hist.watch(c, code, v)
+	}
+	if c.audit != nil {
+		c.audit.watch(c, code, v)
+	}
 }
 
 // Get returns Valuer instance by code.
@@ -331,8 +604,20 @@ func makeValuer(ak AKind) Valuer {
 		res = NewString()
 	case AFloat:
 		res = NewFloat()
+	case ADuration:
+		res = NewDuration()
+	case AStringSlice:
+		res = NewStringSlice()
+	case AIntSlice:
+		res = NewIntSlice()
+	case ASecret:
+		res = NewSecret()
 	default:
-		panic("invalid AKind")
+		d, ok := kindByCode(ak)
+		if !ok {
+			panic("invalid AKind")
+		}
+		res = d.new()
 	}
 
 	return res
@@ -346,3 +631,26 @@ func (c *Config) Walk(f func(code string, v Valuer, init, asked int)) {
 		f(c.list[i].code, c.list[i].av, c.list[i].inited, c.list[i].asked)
 	}
 }
+
+// Origin returns origin of param's effective value. Returns false if
+// param not found or its value was not provided by any source.
+// Values published by UpdateContext get source carried by context.
+func (c *Config) Origin(code string) (Origin, bool) {
+	c.mux.RLock()
+	defer c.mux.RUnlock()
+	idx, ok := c.idx[code]
+	if !ok || c.list[idx].origin == nil {
+		return Origin{}, false
+	}
+	return *c.list[idx].origin, true
+}
+
+// SetOrigin sets origin of param's effective value.
+// Does nothing if param not found.
+func (c *Config) SetOrigin(code string, o Origin) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	if idx, ok := c.idx[code]; ok {
+		c.list[idx].origin = &o
+	}
+}
@@ -2,7 +2,12 @@ package gonfig_test
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/axkit/gonfig"
 )
@@ -129,6 +134,308 @@ func TestInt(t *testing.T) {
 
 }
 
+func TestDuration(t *testing.T) {
+	type store struct {
+		Timeout gonfig.Duration `cfg:"timeout" default:"5s" json:"timeout"`
+	}
+
+	var a store
+
+	if a.Timeout.Val() != gonfig.NonBindedDuration {
+		t.Error("wrong non binded Duration value")
+	}
+
+	cfg := gonfig.New()
+
+	if err := cfg.MustParam("delay", gonfig.ADuration).Parse("xxx"); err == nil {
+		t.Error("error expected")
+	}
+
+	if errs := cfg.BindStruct(&a); len(errs) != 0 {
+		t.Error(errs)
+	}
+
+	if a.Timeout.Val() != 5*time.Second {
+		t.Errorf("default tag ignored. expected 5s, got %s", a.Timeout.String())
+	}
+
+	if err := cfg.MustParam("timeout", gonfig.ADuration).Parse("1m30s"); err != nil {
+		t.Error(err)
+	}
+
+	if a.Timeout.Val() != 90*time.Second {
+		t.Errorf("Parse() failed. expected 1m30s, got %s", a.Timeout.String())
+	}
+
+	buf, err := json.Marshal(&a)
+	if err != nil {
+		t.Error(err)
+	}
+	if string(buf) != `{"timeout":"1m30s"}` {
+		t.Errorf("Marshal() failed, got %s", buf)
+	}
+
+	if err := json.Unmarshal([]byte(`{"timeout" : "250ms"}`), &a); err != nil {
+		t.Error(err)
+	}
+	if a.Timeout.Val() != 250*time.Millisecond {
+		t.Errorf("Unmarshal() failed. expected 250ms, got %s", a.Timeout.String())
+	}
+
+	if err := json.Unmarshal([]byte(`{"timeout" : 1000}`), &a); err != nil {
+		t.Error(err)
+	}
+	if a.Timeout.Val() != time.Microsecond {
+		t.Errorf("Unmarshal() failed. expected 1µs, got %s", a.Timeout.String())
+	}
+
+	var d gonfig.Duration
+	if err := cfg.BindVar("timeout", &d); err != nil {
+		t.Error(err)
+	}
+	if d.Val() != a.Timeout.Val() {
+		t.Error("BindVar() failed")
+	}
+}
+
+func TestStringSlice(t *testing.T) {
+	type store struct {
+		Origins gonfig.StringSlice `cfg:"origins" default:"a.com|b.com" sep:"|" json:"origins"`
+	}
+
+	var a store
+
+	if a.Origins.Val() != nil {
+		t.Error("wrong non binded StringSlice value")
+	}
+
+	cfg := gonfig.New()
+	if errs := cfg.BindStruct(&a); len(errs) != 0 {
+		t.Error(errs)
+	}
+
+	if v := a.Origins.Val(); len(v) != 2 || v[0] != "a.com" || v[1] != "b.com" {
+		t.Errorf("default tag ignored, got %v", v)
+	}
+
+	v := a.Origins.Val()
+	v[0] = "changed"
+	if a.Origins.Val()[0] != "a.com" {
+		t.Error("Val() must return a copy")
+	}
+
+	if err := cfg.MustParam("origins", gonfig.AStringSlice).Parse(`["x.com", "y.com", "z.com"]`); err != nil {
+		t.Error(err)
+	}
+
+	if a.Origins.Len() != 3 || a.Origins.String() != "x.com|y.com|z.com" {
+		t.Errorf("Parse() failed, got %s", a.Origins.String())
+	}
+
+	buf, err := json.Marshal(&a)
+	if err != nil {
+		t.Error(err)
+	}
+	if string(buf) != `{"origins":["x.com","y.com","z.com"]}` {
+		t.Errorf("Marshal() failed, got %s", buf)
+	}
+}
+
+func TestIntSlice(t *testing.T) {
+
+	cfg := gonfig.New()
+
+	var ports gonfig.IntSlice
+	if err := cfg.BindVar("ports", &ports); err != nil {
+		t.Error(err)
+	}
+
+	if err := ports.Parse("80, 443,8080"); err != nil {
+		t.Error(err)
+	}
+
+	if v := ports.Val(); len(v) != 3 || v[2] != 8080 {
+		t.Errorf("Parse() failed, got %v", v)
+	}
+
+	if err := ports.Parse("80,abc"); err == nil {
+		t.Error("error expected")
+	}
+
+	if ports.Len() != 3 {
+		t.Error("failed Parse() must keep old value")
+	}
+
+	if err := ports.Parse("[1,2]"); err != nil {
+		t.Error(err)
+	}
+
+	if ports.String() != "1,2" {
+		t.Errorf("String() failed, got %s", ports.String())
+	}
+
+	p, ok := cfg.Get("ports")
+	if !ok || p.Kind() != gonfig.AIntSlice {
+		t.Error("Get() failed")
+	}
+}
+
+var AIP = gonfig.RegisterKind("AIP", func(s string) (net.IP, error) {
+	ip := net.ParseIP(strings.TrimSpace(s))
+	if ip == nil {
+		return nil, errors.New("invalid ip address")
+	}
+	return ip, nil
+}, net.IP.String)
+
+func TestValue(t *testing.T) {
+	type store struct {
+		Listen gonfig.Value[net.IP] `cfg:"listen_ip" default:"127.0.0.1" json:"listen_ip"`
+	}
+
+	if AIP.String() != "AIP" {
+		t.Errorf("wrong kind name: %s", AIP)
+	}
+
+	var a, b store
+
+	cfg := gonfig.New()
+	if errs := cfg.BindStruct(&a); len(errs) != 0 {
+		t.Error(errs)
+	}
+	cfg.BindStruct(&b)
+
+	if a.Listen.Kind() != AIP {
+		t.Error("wrong kind")
+	}
+
+	if !a.Listen.Val().Equal(net.IPv4(127, 0, 0, 1)) {
+		t.Errorf("default tag ignored, got %s", a.Listen.String())
+	}
+
+	p, ok := cfg.Get("listen_ip")
+	if !ok || p.Kind() != AIP {
+		t.Error("Get() failed")
+	}
+
+	if err := p.Parse("not-ip"); err == nil {
+		t.Error("error expected")
+	}
+
+	if err := p.Parse("10.0.0.1"); err != nil {
+		t.Error(err)
+	}
+
+	if b.Listen.String() != "10.0.0.1" {
+		t.Errorf("not shared a single memory, got %s", b.Listen.String())
+	}
+
+	var ip gonfig.Value[net.IP]
+	if err := cfg.BindVar("listen_ip", &ip); err != nil {
+		t.Error(err)
+	}
+	if ip.String() != "10.0.0.1" {
+		t.Error("BindVar() failed")
+	}
+
+	var i gonfig.Int
+	if err := cfg.BindVar("listen_ip", &i); err == nil {
+		t.Error("error expected")
+	}
+
+	if err := cfg.MustParam("gateway", AIP).Parse("192.168.0.1"); err != nil {
+		t.Error(err)
+	}
+
+	buf, err := json.Marshal(&a)
+	if err != nil {
+		t.Error(err)
+	}
+	if string(buf) != `{"listen_ip":"10.0.0.1"}` {
+		t.Errorf("Marshal() failed, got %s", buf)
+	}
+
+	if err := json.Unmarshal([]byte(`{"listen_ip":"10.0.0.2"}`), &a); err != nil {
+		t.Error(err)
+	}
+	if b.Listen.String() != "10.0.0.2" {
+		t.Error("Unmarshal() failed")
+	}
+
+	var unregistered gonfig.Value[complex128]
+	if unregistered.Kind() != gonfig.Unknown {
+		t.Error("unregistered type must have Unknown kind")
+	}
+
+	if _, err := cfg.Param("c", gonfig.AKind(200)); err != gonfig.ErrUnknownKind {
+		t.Errorf("expected ErrUnknownKind, got %v", err)
+	}
+}
+
+func TestSecret(t *testing.T) {
+	type store struct {
+		Password gonfig.Secret `cfg:"password" default:"qwerty" json:"password"`
+	}
+
+	var a store
+
+	cfg := gonfig.New()
+	if errs := cfg.BindStruct(&a); len(errs) != 0 {
+		t.Error(errs)
+	}
+
+	if a.Password.Reveal() != "qwerty" {
+		t.Errorf("Reveal() failed, got %s", a.Password.Reveal())
+	}
+
+	for _, s := range []string{
+		a.Password.String(),
+		fmt.Sprint(&a.Password),
+		fmt.Sprintf("%v %+v %#v", &a, &a, &a),
+	} {
+		if strings.Contains(s, "qwerty") {
+			t.Errorf("secret is revealed: %s", s)
+		}
+	}
+
+	buf, err := json.Marshal(&a)
+	if err != nil {
+		t.Error(err)
+	}
+	if string(buf) != `{"password":"******"}` {
+		t.Errorf("Marshal() failed, got %s", buf)
+	}
+
+	var changes []string
+	cancel, _ := cfg.OnChange("password", func(old, new string) {
+		changes = append(changes, old, new)
+	})
+	defer cancel()
+
+	p, _ := cfg.Get("password")
+	if err := p.Parse("secret"); err != nil {
+		t.Error(err)
+	}
+	if a.Password.Reveal() != "secret" || gonfig.Plain(p) != "secret" {
+		t.Error("Parse() failed")
+	}
+	if len(changes) != 2 || changes[0] != gonfig.SecretMask || changes[1] != gonfig.SecretMask {
+		t.Errorf("changes must be masked: %v", changes)
+	}
+
+	if err := json.Unmarshal([]byte(`{"password":"abc"}`), &a); err != nil {
+		t.Error(err)
+	}
+	if a.Password.Reveal() != "abc" {
+		t.Error("Unmarshal() failed")
+	}
+
+	var empty gonfig.Secret
+	if empty.String() != "" {
+		t.Error("empty secret must be shown as empty string")
+	}
+}
+
 func Benchmark_intassign(b *testing.B) {
 	ref := new(int)
 	var i int
@@ -1,6 +1,7 @@
 package gonfigenv
 
 import (
+	"encoding/json"
 	"os"
 	"strings"
 
@@ -8,11 +9,15 @@ import (
 )
 
 // EnvSource implements logic or reading application parameters
-// from the environment variables.
+// from the environment variables. Code of param is taken from variable
+// name by gonfig.EnvCode, so DB__POOL_SIZE sets param db.pool.size
+// as written by gonfig.ExportEnv.
 type EnvSource struct {
 	prefix string
 
 	tolower bool
+
+	vars map[string]string
 }
 
 // NewEnvSource returns EnvSource. if tolower is true, the envvar code
@@ -26,15 +31,22 @@ func (s *EnvSource) ApplyTo(g gonfig.Configer, ow bool) error {
 	return s.applyTo(g, ow)
 }
 
+// Location returns name of environment variable the param
+// identified by code was taken from.
+func (s *EnvSource) Location(code string) string {
+	return s.vars[code]
+}
+
 func (s *EnvSource) applyTo(g gonfig.Configer, ow bool) error {
 
+	s.vars = make(map[string]string)
 	for _, e := range os.Environ() {
 		pair := strings.SplitN(e, "=", 2)
 		if !strings.HasPrefix(pair[0], s.prefix) {
 			continue
 		}
 
-		code := pair[0][len(s.prefix):]
+		code := gonfig.EnvCode(pair[0][len(s.prefix):])
 		if s.tolower {
 			code = strings.ToLower(code)
 		}
@@ -47,14 +59,37 @@ func (s *EnvSource) applyTo(g gonfig.Configer, ow bool) error {
 			if err := p.Parse(pair[1]); err != nil {
 				return err
 			}
+			s.vars[code] = pair[0]
+			gonfig.RecordOrigin(g, code, gonfig.Origin{Source: "env", Location: pair[0]})
 			continue
 		}
 
 		// parameter was not found
-		if err := g.MustParam(code, gonfig.AString).Parse(pair[1]); err != nil {
+		if err := g.MustParam(code, kindOf(pair[1])).Parse(pair[1]); err != nil {
 			return err
 		}
+		s.vars[code] = pair[0]
 
 	}
 	return nil
 }
+
+// kindOf returns AStringSlice or AIntSlice if v is JSON array
+// of strings or numbers. Otherwise returns AString.
+func kindOf(v string) gonfig.AKind {
+	v = strings.TrimSpace(v)
+	if !strings.HasPrefix(v, "[") {
+		return gonfig.AString
+	}
+
+	var is []int
+	if err := json.Unmarshal([]byte(v), &is); err == nil {
+		return gonfig.AIntSlice
+	}
+
+	var ss []string
+	if err := json.Unmarshal([]byte(v), &ss); err == nil {
+		return gonfig.AStringSlice
+	}
+	return gonfig.AString
+}
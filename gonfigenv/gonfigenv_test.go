@@ -1,6 +1,11 @@
 package gonfigenv_test
 
 import (
+	"bufio"
+	"bytes"
+	"os"
+	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/axkit/gonfig"
@@ -19,3 +24,107 @@ func TestEnvSource_CopyTo(t *testing.T) {
 		t.Error("no gopath var")
 	}
 }
+
+func TestEnvSource_Slices(t *testing.T) {
+
+	os.Setenv("GONFIGTEST_ORIGINS", `["a.com","b.com"]`)
+	os.Setenv("GONFIGTEST_PORTS", `[80, 443]`)
+	os.Setenv("GONFIGTEST_BROKERS", "k1:9092; k2:9092")
+	defer func() {
+		os.Unsetenv("GONFIGTEST_ORIGINS")
+		os.Unsetenv("GONFIGTEST_PORTS")
+		os.Unsetenv("GONFIGTEST_BROKERS")
+	}()
+
+	cfg := gonfig.New()
+
+	var brokers gonfig.StringSlice
+	if err := cfg.BindVar("brokers", &brokers); err != nil {
+		t.Error(err)
+	}
+	brokers.SetSeparator(";")
+
+	if err := gonfigenv.NewEnvSource("GONFIGTEST_", true).ApplyTo(cfg, true); err != nil {
+		t.Error(err)
+	}
+
+	if v, ok := cfg.Get("origins"); !ok || v.Kind() != gonfig.AStringSlice {
+		t.Error("origins expected as AStringSlice")
+	}
+
+	if v, ok := cfg.Get("ports"); !ok || v.Kind() != gonfig.AIntSlice {
+		t.Error("ports expected as AIntSlice")
+	} else if ports := v.(*gonfig.IntSlice).Val(); len(ports) != 2 || ports[1] != 443 {
+		t.Errorf("wrong ports: %v", ports)
+	}
+
+	if b := brokers.Val(); len(b) != 2 || b[1] != "k2:9092" {
+		t.Errorf("wrong brokers: %v", b)
+	}
+}
+
+func TestEnvSource_Export(t *testing.T) {
+
+	type store struct {
+		Size  gonfig.Int      `cfg:"db.pool.size" default:"10"`
+		Host  gonfig.String   `cfg:"db_host" default:"localhost"`
+		Ports gonfig.IntSlice `cfg:"ports" default:"80,443"`
+	}
+
+	var a store
+	src := gonfig.New()
+	src.BindStruct(&a)
+	a.Size.Set(20)
+
+	var buf bytes.Buffer
+	e := gonfig.Exporter{Format: gonfig.ExportEnv, EnvPrefix: "GONFIGRT_"}
+	if err := e.Export(&buf, src); err != nil {
+		t.Fatal(err)
+	}
+
+	sc := bufio.NewScanner(&buf)
+	for sc.Scan() {
+		name, val, _ := strings.Cut(sc.Text(), "=")
+		v, err := strconv.Unquote(val)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Setenv(name, v)
+	}
+
+	var b store
+	dst := gonfig.New()
+	dst.BindStruct(&b)
+	if err := gonfigenv.NewEnvSource("GONFIGRT_", true).ApplyTo(dst, true); err != nil {
+		t.Fatal(err)
+	}
+
+	if b.Size.Val() != 20 || b.Host.Val() != "localhost" || len(b.Ports.Val()) != 2 {
+		t.Errorf("unexpected values %d %s %v", b.Size.Val(), b.Host.Val(), b.Ports.Val())
+	}
+	if dst.IsExist("db_pool_size") {
+		t.Error("unexpected param db_pool_size")
+	}
+}
+
+func TestEnvSource_Origin(t *testing.T) {
+
+	t.Setenv("GONFIGORIGIN_PORT", "8080")
+
+	var c struct {
+		Port gonfig.Int    `cfg:"port" default:"80"`
+		Host gonfig.String `cfg:"host"`
+	}
+	cfg := gonfig.New()
+	cfg.BindStruct(&c)
+
+	if err := gonfigenv.NewEnvSource("GONFIGORIGIN_", true).ApplyTo(cfg, true); err != nil {
+		t.Fatal(err)
+	}
+	if o, ok := cfg.Origin("port"); !ok || o.String() != "env:GONFIGORIGIN_PORT" {
+		t.Errorf("unexpected origin %v", o)
+	}
+	if r := cfg.Audit(); len(r.Defaulted) != 1 || r.Defaulted[0] != "host" {
+		t.Errorf("unexpected defaulted params %v", r.Defaulted)
+	}
+}
@@ -0,0 +1,307 @@
+// Package gonfigfile implements reading application parameters
+// from YAML, JSON and TOML files.
+package gonfigfile
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/axkit/gonfig"
+	"gopkg.in/yaml.v3"
+)
+
+// Format represents file format.
+type Format uint8
+
+const (
+	// Auto detects format by file extension.
+	Auto Format = 0
+
+	// YAML represents .yaml and .yml files.
+	YAML Format = 1
+
+	// JSON represents .json files.
+	JSON Format = 2
+
+	// TOML represents .toml files.
+	TOML Format = 3
+)
+
+// DefaultSeparator is used to join nested keys if
+// separator was not specified.
+const DefaultSeparator = "."
+
+// ErrUnknownFormat raises when file format can't be detected by extension.
+var ErrUnknownFormat = errors.New("unknown config file format")
+
+// String implements Stringer interface.
+func (f Format) String() string {
+	switch f {
+	case YAML:
+		return "YAML"
+	case JSON:
+		return "JSON"
+	case TOML:
+		return "TOML"
+	}
+	return "Auto"
+}
+
+// FileSource implements logic of reading application parameters
+// from the config file.
+type FileSource struct {
+	path   string
+	format Format
+	sep    string
+}
+
+// NewFileSource returns FileSource reading file path. Nested keys
+// are joined by sep, e.g. "db.pool.size". If sep is empty,
+// DefaultSeparator is used. Format is detected by file extension.
+func NewFileSource(path string, sep string) *FileSource {
+	if sep == "" {
+		sep = DefaultSeparator
+	}
+	return &FileSource{path: path, sep: sep}
+}
+
+// WithFormat sets file format explicitly.
+func (s *FileSource) WithFormat(f Format) *FileSource {
+	s.format = f
+	return s
+}
+
+// Path returns path of the config file.
+func (s *FileSource) Path() string {
+	return s.path
+}
+
+// ApplyTo reads the file and applies parameters to config container.
+// Kind of new parameter is inferred from the value type.
+// Existing parameters are overwritten if ow is true.
+func (s *FileSource) ApplyTo(g gonfig.Configer, ow bool) error {
+	kv, err := s.Read()
+	if err != nil {
+		return err
+	}
+	return Apply(g, kv, ow)
+}
+
+// Location returns path of the config file.
+func (s *FileSource) Location(code string) string {
+	return s.path
+}
+
+// Read reads and flattens the file without applying it.
+func (s *FileSource) Read() (map[string]Value, error) {
+	buf, err := os.ReadFile(s.path)
+	if err != nil {
+		return nil, err
+	}
+
+	f := s.format
+	if f == Auto {
+		if f = FormatOf(s.path); f == Auto {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownFormat, s.path)
+		}
+	}
+
+	m, err := Decode(buf, f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", s.path, err)
+	}
+	return Flatten(m, s.sep), nil
+}
+
+// FormatOf returns file format by file extension.
+// Returns Auto if extension is unknown.
+func FormatOf(path string) Format {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return YAML
+	case ".json":
+		return JSON
+	case ".toml":
+		return TOML
+	}
+	return Auto
+}
+
+// Decode decodes buf into nested map.
+func Decode(buf []byte, f Format) (map[string]interface{}, error) {
+	m := make(map[string]interface{})
+	var err error
+	switch f {
+	case YAML:
+		err = yaml.Unmarshal(buf, &m)
+	case JSON:
+		dec := json.NewDecoder(bytes.NewReader(buf))
+		dec.UseNumber()
+		err = dec.Decode(&m)
+	case TOML:
+		err = toml.Unmarshal(buf, &m)
+	default:
+		err = ErrUnknownFormat
+	}
+	return m, err
+}
+
+// Value is a flattened file value.
+type Value struct {
+	// Raw is a value converted to string accepted by Valuer.Parse.
+	Raw string
+
+	// Kind is a kind inferred from the value type.
+	Kind gonfig.AKind
+}
+
+// Flatten converts nested map into flat map where keys are
+// joined by sep. Items of arrays of objects are keyed by index.
+func Flatten(m map[string]interface{}, sep string) map[string]Value {
+	res := make(map[string]Value)
+	flatten(res, "", sep, m)
+	return res
+}
+
+func flatten(res map[string]Value, prefix, sep string, v interface{}) {
+	join := func(k string) string {
+		if prefix == "" {
+			return k
+		}
+		return prefix + sep + k
+	}
+
+	switch x := v.(type) {
+	case map[string]interface{}:
+		for k, item := range x {
+			flatten(res, join(k), sep, item)
+		}
+	case map[interface{}]interface{}:
+		for k, item := range x {
+			flatten(res, join(fmt.Sprint(k)), sep, item)
+		}
+	case []map[string]interface{}:
+		for i := range x {
+			flatten(res, join(strconv.Itoa(i)), sep, x[i])
+		}
+	case []interface{}:
+		if v, ok := slice(x); ok {
+			res[prefix] = v
+			return
+		}
+		for i := range x {
+			flatten(res, join(strconv.Itoa(i)), sep, x[i])
+		}
+	default:
+		if prefix != "" {
+			res[prefix] = scalar(x)
+		}
+	}
+}
+
+// slice converts array of scalars into JSON array of numbers
+// (AIntSlice) or strings (AStringSlice). Returns false if any
+// item is not a scalar.
+func slice(x []interface{}) (Value, bool) {
+	ak := gonfig.AIntSlice
+	if len(x) == 0 {
+		ak = gonfig.AStringSlice
+	}
+	items := make([]string, len(x))
+	for i := range x {
+		switch x[i].(type) {
+		case map[string]interface{}, map[interface{}]interface{}, []interface{}, nil:
+			return Value{}, false
+		}
+		v := scalar(x[i])
+		if v.Kind != gonfig.AInt {
+			ak = gonfig.AStringSlice
+		}
+		items[i] = v.Raw
+	}
+
+	if ak == gonfig.AIntSlice {
+		return Value{Raw: "[" + strings.Join(items, ",") + "]", Kind: ak}, true
+	}
+
+	buf, _ := json.Marshal(items)
+	return Value{Raw: string(buf), Kind: ak}, true
+}
+
+func scalar(v interface{}) Value {
+	switch x := v.(type) {
+	case bool:
+		return Value{Raw: strconv.FormatBool(x), Kind: gonfig.ABool}
+	case int:
+		return Value{Raw: strconv.Itoa(x), Kind: gonfig.AInt}
+	case int64:
+		return Value{Raw: strconv.FormatInt(x, 10), Kind: gonfig.AInt}
+	case uint64:
+		return Value{Raw: strconv.FormatUint(x, 10), Kind: gonfig.AInt}
+	case float64:
+		return Value{Raw: strconv.FormatFloat(x, 'g', -1, 64), Kind: gonfig.AFloat}
+	case json.Number:
+		if _, err := x.Int64(); err == nil {
+			return Value{Raw: x.String(), Kind: gonfig.AInt}
+		}
+		return Value{Raw: x.String(), Kind: gonfig.AFloat}
+	case time.Time:
+		return Value{Raw: x.Format(time.RFC3339Nano), Kind: gonfig.AString}
+	case nil:
+		return Value{Raw: "", Kind: gonfig.AString}
+	}
+	return Value{Raw: fmt.Sprint(v), Kind: gonfig.AString}
+}
+
+// Apply applies flattened values to config container in order of codes.
+// New parameters are created with inferred kind.
+// Existing parameters are overwritten if ow is true. Numbers applied
+// to existing ADuration params are seconds, e.g. "timeout: 30".
+// Values referencing params of kv are applied after them.
+func Apply(g gonfig.Configer, kv map[string]Value, ow bool) error {
+	codes := make([]string, 0, len(kv))
+	for code := range kv {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+
+	return gonfig.ApplyOrdered(codes, func(code string) error {
+		v := kv[code]
+		ak := v.Kind
+		if p, ok := g.Get(code); ok {
+			if !ow {
+				return nil
+			}
+			// existing parameter keeps own kind and parses the value.
+			ak = p.Kind()
+		}
+
+		p, err := g.Param(code, ak)
+		if err != nil {
+			return err
+		}
+
+		if err := p.Parse(rawFor(ak, v)); err != nil {
+			return fmt.Errorf("param '%s': %w", code, err)
+		}
+		return nil
+	})
+}
+
+// rawFor returns v.Raw as parsed by param of kind ak.
+// Numbers are seconds for ADuration params.
+func rawFor(ak gonfig.AKind, v Value) string {
+	if ak == gonfig.ADuration && (v.Kind == gonfig.AInt || v.Kind == gonfig.AFloat) {
+		return v.Raw + "s"
+	}
+	return v.Raw
+}
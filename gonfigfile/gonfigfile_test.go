@@ -0,0 +1,165 @@
+package gonfigfile_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/axkit/gonfig"
+	"github.com/axkit/gonfig/gonfigfile"
+)
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestFileSource_ApplyTo(t *testing.T) {
+
+	files := map[string]string{
+		"app.yaml": `
+db:
+  host: db.local
+  pool:
+    size: 10
+  ratio: 0.5
+debug: true
+origins: [a.com, b.com]
+ports: [80, 443]
+servers:
+  - port: 8080
+  - port: 8081
+`,
+		"app.json": `{
+	"db": {"host": "db.local", "pool": {"size": 10}, "ratio": 0.5},
+	"debug": true,
+	"origins": ["a.com", "b.com"],
+	"ports": [80, 443],
+	"servers": [{"port": 8080}, {"port": 8081}]
+}`,
+		"app.toml": `
+debug = true
+origins = ["a.com", "b.com"]
+ports = [80, 443]
+
+[db]
+host = "db.local"
+ratio = 0.5
+
+[db.pool]
+size = 10
+
+[[servers]]
+port = 8080
+
+[[servers]]
+port = 8081
+`,
+	}
+
+	for name, content := range files {
+		t.Run(name, func(t *testing.T) {
+			path := writeFile(t, name, content)
+
+			cfg := gonfig.New()
+			if err := gonfigfile.NewFileSource(path, "").ApplyTo(cfg, false); err != nil {
+				t.Fatal(err)
+			}
+
+			expected := map[string]struct {
+				kind gonfig.AKind
+				val  string
+			}{
+				"db.host":        {gonfig.AString, "db.local"},
+				"db.pool.size":   {gonfig.AInt, "10"},
+				"db.ratio":       {gonfig.AFloat, "0.500000"},
+				"debug":          {gonfig.ABool, "true"},
+				"origins":        {gonfig.AStringSlice, "a.com,b.com"},
+				"ports":          {gonfig.AIntSlice, "80,443"},
+				"servers.1.port": {gonfig.AInt, "8081"},
+			}
+
+			for code, e := range expected {
+				v, ok := cfg.Get(code)
+				if !ok {
+					t.Errorf("param '%s' not found", code)
+					continue
+				}
+				if v.Kind() != e.kind {
+					t.Errorf("param '%s': expected kind %s, got %s", code, e.kind, v.Kind())
+				}
+				if s := v.(interface{ String() string }).String(); s != e.val {
+					t.Errorf("param '%s': expected %s, got %s", code, e.val, s)
+				}
+			}
+		})
+	}
+}
+
+func TestFileSource_Overwrite(t *testing.T) {
+
+	path := writeFile(t, "app.yml", "port: 8080\ntimeout: 5s\n")
+
+	cfg := gonfig.New()
+	cfg.MustParam("port", gonfig.AInt).Parse("80")
+	cfg.MustParam("timeout", gonfig.ADuration).Parse("1s")
+
+	src := gonfigfile.NewFileSource(path, "")
+	if err := src.ApplyTo(cfg, false); err != nil {
+		t.Error(err)
+	}
+
+	if v, _ := cfg.Get("port"); v.(*gonfig.Int).Val() != 80 {
+		t.Error("value overwritten while ow is false")
+	}
+
+	if err := src.ApplyTo(cfg, true); err != nil {
+		t.Error(err)
+	}
+
+	if v, _ := cfg.Get("port"); v.(*gonfig.Int).Val() != 8080 {
+		t.Error("value is not overwritten while ow is true")
+	}
+
+	if v, _ := cfg.Get("timeout"); v.Kind() != gonfig.ADuration || v.(*gonfig.Duration).String() != "5s" {
+		t.Error("existing param kind must be kept")
+	}
+
+	// bare numbers are seconds.
+	path = writeFile(t, "app.yml", "timeout: 30\n")
+	if err := gonfigfile.NewFileSource(path, "").ApplyTo(cfg, true); err != nil {
+		t.Error(err)
+	}
+	if v, _ := cfg.Get("timeout"); v.(*gonfig.Duration).Val() != 30*time.Second {
+		t.Errorf("expected 30s, got %s", v)
+	}
+}
+
+func TestFileSource_Errors(t *testing.T) {
+
+	cfg := gonfig.New()
+
+	if err := gonfigfile.NewFileSource("app.ini", "").ApplyTo(cfg, false); err == nil {
+		t.Error("error expected for missing file")
+	}
+
+	path := writeFile(t, "app.ini", "port=1")
+	if err := gonfigfile.NewFileSource(path, "").ApplyTo(cfg, false); err == nil {
+		t.Error("error expected for unknown format")
+	}
+
+	if err := gonfigfile.NewFileSource(path, "").WithFormat(gonfigfile.TOML).ApplyTo(cfg, false); err != nil {
+		t.Error(err)
+	}
+
+	cfg.MustParam("size", gonfig.AInt)
+	path = writeFile(t, "bad.json", `{"size": "big"}`)
+	if err := gonfigfile.NewFileSource(path, "").ApplyTo(cfg, true); err == nil {
+		t.Error("error expected for unparsable value")
+	}
+}
@@ -0,0 +1,10 @@
+//go:build !linux
+
+package gonfigfile
+
+import "context"
+
+// notify is not supported, file is polled.
+func notify(ctx context.Context, path string) (<-chan struct{}, error) {
+	return nil, errNotSupported
+}
@@ -0,0 +1,250 @@
+package gonfigfile
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/axkit/gonfig"
+)
+
+const (
+	// DefaultDebounce is a delay after the last file event
+	// before the file is reloaded.
+	DefaultDebounce = 100 * time.Millisecond
+
+	// DefaultPollInterval is an interval of file checks
+	// if file system notifications are not available.
+	DefaultPollInterval = time.Second
+)
+
+// errNotSupported raises if file system notifications
+// are not supported by OS.
+var errNotSupported = errors.New("file system notifications are not supported")
+
+// Watcher reloads config file on change and applies changed
+// parameters to config container.
+//
+// On Linux changes are detected by inotify, on other systems or if
+// inotify is not available the file is polled.
+type Watcher struct {
+	src      *FileSource
+	cfg      Config
+	debounce time.Duration
+	interval time.Duration
+	polling  bool
+	onError  func(error)
+	onReload func(changed []string)
+	dec      gonfig.Decrypter
+}
+
+// Config is a container updated by Watcher. It's implemented by
+// *gonfig.Config and views returned by its Sub method.
+type Config interface {
+	gonfig.Configer
+	gonfig.Updater
+}
+
+// NewWatcher returns Watcher of the file read by src.
+func NewWatcher(src *FileSource, cfg Config) *Watcher {
+	return &Watcher{
+		src:      src,
+		cfg:      cfg,
+		debounce: DefaultDebounce,
+		interval: DefaultPollInterval,
+		onError:  func(error) {},
+		onReload: func([]string) {},
+	}
+}
+
+// WithDebounce sets delay after the last file event before reload.
+// Editors write files in several steps, debounce makes reload happen once.
+func (w *Watcher) WithDebounce(d time.Duration) *Watcher {
+	w.debounce = d
+	return w
+}
+
+// WithPolling forces polling the file with interval d.
+func (w *Watcher) WithPolling(d time.Duration) *Watcher {
+	w.polling = true
+	w.interval = d
+	return w
+}
+
+// WithDecrypter makes watcher decrypt values in form enc:<base64>.
+func (w *Watcher) WithDecrypter(d gonfig.Decrypter) *Watcher {
+	w.dec = d
+	return w
+}
+
+// OnError sets function receiving reload errors.
+func (w *Watcher) OnError(f func(error)) *Watcher {
+	w.onError = f
+	return w
+}
+
+// OnReload sets function receiving codes of params changed by reload.
+func (w *Watcher) OnReload(f func(changed []string)) *Watcher {
+	w.onReload = f
+	return w
+}
+
+// Run watches the file until ctx is done. Errors of reload are passed
+// to function set by OnError, watching continues. Returns error if
+// watching can't be started.
+func (w *Watcher) Run(ctx context.Context) error {
+	var events <-chan struct{}
+	if !w.polling {
+		ch, err := notify(ctx, w.src.path)
+		if err != nil && !errors.Is(err, errNotSupported) {
+			return err
+		}
+		events = ch
+	}
+
+	if events == nil {
+		events = poll(ctx, w.src.path, w.interval)
+	}
+
+	timer := time.NewTimer(time.Hour)
+	timer.Stop()
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case _, ok := <-events:
+			if !ok {
+				return nil
+			}
+			timer.Reset(w.debounce)
+		case <-timer.C:
+			changed, err := w.Reload()
+			if err != nil {
+				w.onError(err)
+				continue
+			}
+			if len(changed) > 0 {
+				w.onReload(changed)
+			}
+		}
+	}
+}
+
+// Reload reads the file and applies params which values differ from
+// values in container. Existing params are changed and new params are
+// created together by Config.UpdateContext with the file path as
+// source of changes, if the file can't be read or any value is
+// invalid nothing is applied. Changes of static params after Freeze
+// are skipped, see gonfig.ParamInfo.Pending. Returns codes of
+// changed params.
+func (w *Watcher) Reload() ([]string, error) {
+	kv, err := w.src.Read()
+	if err != nil {
+		return nil, err
+	}
+
+	if w.dec != nil {
+		for code, v := range kv {
+			if v.Raw, err = gonfig.DecryptValue(w.dec, v.Raw); err != nil {
+				return nil, fmt.Errorf("%s: param '%s': %w", w.src.path, code, err)
+			}
+			kv[code] = v
+		}
+	}
+
+	codes := make([]string, 0, len(kv))
+	for code := range kv {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+
+	var changed []string
+	ctx := gonfig.WithSource(context.Background(), w.src.path)
+	err = w.cfg.UpdateContext(ctx, func(tx gonfig.Tx) error {
+		var added []string
+		for _, code := range codes {
+			cur, ok := tx.Get(code)
+			if !ok {
+				added = append(added, code)
+				continue
+			}
+
+			// values are compared after references are resolved
+			// by Parse, unchanged values are published as no-op.
+			if err := tx.Parse(code, rawFor(cur.Kind(), kv[code])); err != nil {
+				if errors.Is(err, gonfig.ErrStatic) {
+					// value is pending till restart.
+					continue
+				}
+				return fmt.Errorf("%s: param '%s': %w", w.src.path, code, err)
+			}
+			if v, _ := tx.Get(code); gonfig.Plain(v) != gonfig.Plain(cur) {
+				changed = append(changed, code)
+			}
+		}
+
+		if len(added) == 0 {
+			return nil
+		}
+		tc, ok := tx.(gonfig.TxCreator)
+		if !ok {
+			return fmt.Errorf("%s: params can't be created by transaction", w.src.path)
+		}
+		return gonfig.ApplyOrdered(added, func(code string) error {
+			if err := tc.Create(code, kv[code].Kind, kv[code].Raw); err != nil {
+				return fmt.Errorf("%s: param '%s': %w", w.src.path, code, err)
+			}
+			changed = append(changed, code)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(changed)
+	return changed, nil
+}
+
+// poll sends event to returned channel if file modification time
+// or size changed.
+func poll(ctx context.Context, path string, interval time.Duration) <-chan struct{} {
+	ch := make(chan struct{}, 1)
+
+	stat := func() (time.Time, int64) {
+		fi, err := os.Stat(path)
+		if err != nil {
+			return time.Time{}, -1
+		}
+		return fi.ModTime(), fi.Size()
+	}
+
+	go func() {
+		defer close(ch)
+		mt, sz := stat()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				nmt, nsz := stat()
+				if nmt.Equal(mt) && nsz == sz {
+					continue
+				}
+				mt, sz = nmt, nsz
+				select {
+				case ch <- struct{}{}:
+				default:
+				}
+			}
+		}
+	}()
+	return ch
+}
@@ -0,0 +1,184 @@
+package gonfigfile_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/axkit/gonfig"
+	"github.com/axkit/gonfig/gonfigfile"
+)
+
+func TestWatcher_Reload(t *testing.T) {
+
+	type store struct {
+		Port  gonfig.Int    `cfg:"port" min:"1"`
+		Host  gonfig.String `cfg:"host"`
+		Ratio gonfig.Float  `cfg:"ratio"`
+	}
+
+	path := writeFile(t, "app.yaml", "port: 80\nhost: a\nratio: 0.5\n")
+	src := gonfigfile.NewFileSource(path, "")
+
+	var s store
+	cfg := gonfig.New()
+	if err := src.ApplyTo(cfg, true); err != nil {
+		t.Fatal(err)
+	}
+	cfg.BindStruct(&s)
+
+	w := gonfigfile.NewWatcher(src, cfg)
+
+	changed, err := w.Reload()
+	if err != nil || len(changed) != 0 {
+		t.Errorf("nothing expected to change, got %v, %v", changed, err)
+	}
+
+	os.WriteFile(path, []byte("port: 81\nhost: a\nratio: 0.5\nnew: 1\n"), 0600)
+	changed, err = w.Reload()
+	if err != nil || len(changed) != 2 || changed[0] != "new" || changed[1] != "port" {
+		t.Errorf("unexpected changes %v, %v", changed, err)
+	}
+	if s.Port.Val() != 81 {
+		t.Error("port is not reloaded")
+	}
+
+	os.WriteFile(path, []byte("port: 0\nhost: b\n"), 0600)
+	if _, err := w.Reload(); err == nil {
+		t.Error("error expected")
+	}
+	if s.Port.Val() != 81 || s.Host.Val() != "a" {
+		t.Error("invalid file must not be applied partially")
+	}
+
+	os.WriteFile(path, []byte("port: [broken\n"), 0600)
+	if _, err := w.Reload(); err == nil {
+		t.Error("error expected")
+	}
+}
+
+func TestWatcher_ReloadInterpolated(t *testing.T) {
+
+	path := writeFile(t, "app.yaml", "host: a\nurl: http://${host}/\n")
+	src := gonfigfile.NewFileSource(path, "")
+
+	cfg := gonfig.New().WithInterpolation()
+	if err := src.ApplyTo(cfg, true); err != nil {
+		t.Fatal(err)
+	}
+	w := gonfigfile.NewWatcher(src, cfg)
+
+	changed, err := w.Reload()
+	if err != nil || len(changed) != 0 {
+		t.Errorf("nothing expected to change, got %v, %v", changed, err)
+	}
+
+	os.WriteFile(path, []byte("host: b\nurl: http://${host}/\n"), 0600)
+	changed, err = w.Reload()
+	if err != nil || len(changed) != 2 || changed[0] != "host" || changed[1] != "url" {
+		t.Errorf("unexpected changes %v, %v", changed, err)
+	}
+	if p, _ := cfg.Get("url"); gonfig.Plain(p) != "http://b/" {
+		t.Errorf("unexpected url %s", gonfig.Plain(p))
+	}
+}
+
+func TestWatcher_ReloadNew(t *testing.T) {
+
+	path := writeFile(t, "app.yaml", "host: a\n")
+	src := gonfigfile.NewFileSource(path, "")
+
+	cfg := gonfig.New().WithInterpolation()
+	if err := src.ApplyTo(cfg, true); err != nil {
+		t.Fatal(err)
+	}
+	w := gonfigfile.NewWatcher(src, cfg)
+
+	// new key url fails, neither host changes nor new keys are created.
+	os.WriteFile(path, []byte("host: b\nport: 80\nurl: http://${missing}\n"), 0600)
+	if _, err := w.Reload(); !errors.Is(err, gonfig.ErrUnknownRef) {
+		t.Errorf("expected unknown reference, got %v", err)
+	}
+	if v, _ := cfg.Get("host"); gonfig.Plain(v) != "a" || cfg.IsExist("port") || cfg.IsExist("url") {
+		t.Error("invalid file must not be applied partially")
+	}
+
+	// new keys may reference each other in any order.
+	os.WriteFile(path, []byte("host: b\napi: ${url}/api\nurl: http://${host}\n"), 0600)
+	changed, err := w.Reload()
+	if err != nil || strings.Join(changed, ",") != "api,host,url" {
+		t.Errorf("unexpected changes %v, %v", changed, err)
+	}
+	if v, _ := cfg.Get("api"); gonfig.Plain(v) != "http://b/api" {
+		t.Errorf("unexpected value %s", gonfig.Plain(v))
+	}
+}
+
+func TestWatcher_Run(t *testing.T) {
+
+	for _, polling := range []bool{false, true} {
+		path := filepath.Join(t.TempDir(), "app.json")
+		os.WriteFile(path, []byte(`{"port": 80}`), 0600)
+
+		src := gonfigfile.NewFileSource(path, "")
+		cfg := gonfig.New()
+		if err := src.ApplyTo(cfg, true); err != nil {
+			t.Fatal(err)
+		}
+
+		var port gonfig.Int
+		cfg.BindVar("port", &port)
+
+		reloaded := make(chan []string, 1)
+		var mux sync.Mutex
+		var errs []error
+		w := gonfigfile.NewWatcher(src, cfg).
+			WithDebounce(20 * time.Millisecond).
+			OnReload(func(changed []string) { reloaded <- changed }).
+			OnError(func(err error) {
+				mux.Lock()
+				errs = append(errs, err)
+				mux.Unlock()
+			})
+		if polling {
+			w.WithPolling(10 * time.Millisecond)
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error)
+		go func() { done <- w.Run(ctx) }()
+
+		// give watcher time to start.
+		time.Sleep(50 * time.Millisecond)
+
+		// editors replace file by renaming.
+		tmp := path + ".tmp"
+		os.WriteFile(tmp, []byte(`{"port": 8080, "host": "a"}`), 0600)
+		os.Rename(tmp, path)
+
+		select {
+		case changed := <-reloaded:
+			if len(changed) != 2 || port.Val() != 8080 {
+				t.Errorf("polling=%t: unexpected changes %v", polling, changed)
+			}
+		case <-time.After(2 * time.Second):
+			t.Errorf("polling=%t: file is not reloaded", polling)
+		}
+
+		cancel()
+		if err := <-done; err != nil {
+			t.Error(err)
+		}
+
+		mux.Lock()
+		if len(errs) != 0 {
+			t.Errorf("polling=%t: unexpected errors %v", polling, errs)
+		}
+		mux.Unlock()
+	}
+}
@@ -0,0 +1,270 @@
+// Package gonfigflag implements reading application parameters
+// from the command line.
+package gonfigflag
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/axkit/gonfig"
+)
+
+// ErrHelp is returned by ApplyTo if -h or --help flag
+// was found. Help is printed already.
+var ErrHelp = flag.ErrHelp
+
+// ErrNotParsed raises when FlagSetSource applies flag set
+// which was not parsed yet.
+var ErrNotParsed = errors.New("flag set is not parsed")
+
+// FlagSource implements logic of reading application parameters
+// from the command line arguments.
+//
+// Supported forms are --code=value, --code value, --bool
+// and --no-bool. Single dash is accepted as well. Arguments
+// after "--" and not starting with dash are positional.
+type FlagSource struct {
+	args   []string
+	rest   []string
+	output io.Writer
+	flags  map[string]string
+}
+
+// NewFlagSource returns FlagSource parsing args.
+// If args is nil, os.Args[1:] is used.
+func NewFlagSource(args []string) *FlagSource {
+	if args == nil && len(os.Args) > 1 {
+		args = os.Args[1:]
+	}
+	return &FlagSource{args: args, output: os.Stderr}
+}
+
+// SetOutput sets destination of help output. Default is os.Stderr.
+func (s *FlagSource) SetOutput(w io.Writer) {
+	s.output = w
+}
+
+// Args returns positional arguments found by ApplyTo.
+func (s *FlagSource) Args() []string {
+	return s.rest
+}
+
+// ApplyTo parses command line arguments and applies them to config
+// container. Parameter not found in container is created as AString,
+// or ABool if value is omitted. Existing parameters are overwritten
+// if ow is true.
+//
+// Value starting with dash is taken as the next flag, except negative
+// numbers given to existing AInt, AFloat, ADuration or AIntSlice params.
+//
+// If -h or --help is found, help is printed, nothing is applied and
+// ErrHelp is returned.
+func (s *FlagSource) ApplyTo(g gonfig.Configer, ow bool) error {
+
+	s.rest = nil
+	s.flags = make(map[string]string)
+
+	for _, arg := range s.args {
+		if arg == "--" {
+			break
+		}
+		if arg == "-h" || arg == "--help" || arg == "-help" || arg == "--h" {
+			PrintHelp(s.output, g, defaults(g))
+			return ErrHelp
+		}
+	}
+
+	for i := 0; i < len(s.args); i++ {
+		arg := s.args[i]
+		if arg == "--" {
+			s.rest = append(s.rest, s.args[i+1:]...)
+			break
+		}
+
+		if len(arg) < 2 || arg[0] != '-' {
+			s.rest = append(s.rest, arg)
+			continue
+		}
+
+		name := strings.TrimPrefix(arg[1:], "-")
+		code, val, hasVal := strings.Cut(name, "=")
+		ak := gonfig.AString
+		if !hasVal {
+			p, ok := g.Get(code)
+			switch {
+			case ok && p.Kind() == gonfig.ABool:
+				val = "true"
+			case !ok && strings.HasPrefix(code, "no-"):
+				code, val, ak = code[3:], "false", gonfig.ABool
+			case i+1 < len(s.args) && (!strings.HasPrefix(s.args[i+1], "-") || ok && isNegative(p.Kind(), s.args[i+1])):
+				i++
+				val = s.args[i]
+			case !ok:
+				val, ak = "true", gonfig.ABool
+			default:
+				return fmt.Errorf("flag needs an argument: %s", arg)
+			}
+		}
+
+		if err := apply(g, code, val, ak, ow); err != nil {
+			return fmt.Errorf("flag %s: %w", arg, err)
+		}
+		s.flags[code] = arg
+	}
+
+	return nil
+}
+
+// isNegative returns true if s is a negative number and params of
+// kind ak are numeric.
+func isNegative(ak gonfig.AKind, s string) bool {
+	switch ak {
+	case gonfig.AInt, gonfig.AFloat, gonfig.ADuration, gonfig.AIntSlice:
+		return len(s) > 1 && s[0] == '-' && (s[1] >= '0' && s[1] <= '9' || s[1] == '.')
+	}
+	return false
+}
+
+// Location returns command line flag the param identified
+// by code was taken from.
+func (s *FlagSource) Location(code string) string {
+	return s.flags[code]
+}
+
+// FlagSetSource applies flags of standard flag.FlagSet to config container.
+// Flag name is used as parameter code.
+type FlagSetSource struct {
+	fs *flag.FlagSet
+}
+
+// NewFlagSetSource returns FlagSetSource. Flag set must be parsed
+// before ApplyTo call.
+func NewFlagSetSource(fs *flag.FlagSet) *FlagSetSource {
+	return &FlagSetSource{fs: fs}
+}
+
+// Location returns flag name the param identified by code was taken from.
+func (s *FlagSetSource) Location(code string) string {
+	if f := s.fs.Lookup(code); f != nil {
+		return "-" + f.Name
+	}
+	return ""
+}
+
+// ApplyTo applies flags to config container. Kind of new parameter
+// is inferred from the flag value type. Flags set explicitly overwrite
+// existing parameters if ow is true. Default values of flags are used
+// only if parameter is not in container.
+func (s *FlagSetSource) ApplyTo(g gonfig.Configer, ow bool) error {
+	if !s.fs.Parsed() {
+		return ErrNotParsed
+	}
+
+	set := make(map[string]bool)
+	s.fs.Visit(func(f *flag.Flag) {
+		set[f.Name] = true
+	})
+
+	var err error
+	s.fs.VisitAll(func(f *flag.Flag) {
+		if err != nil {
+			return
+		}
+		if e := apply(g, f.Name, f.Value.String(), kindOf(f.Value), ow && set[f.Name]); e != nil {
+			err = fmt.Errorf("flag -%s: %w", f.Name, e)
+		}
+	})
+	return err
+}
+
+// kindOf infers AKind from the flag value type.
+func kindOf(v flag.Value) gonfig.AKind {
+	g, ok := v.(flag.Getter)
+	if !ok {
+		return gonfig.AString
+	}
+
+	switch g.Get().(type) {
+	case bool:
+		return gonfig.ABool
+	case int, int64, uint, uint64:
+		return gonfig.AInt
+	case float64:
+		return gonfig.AFloat
+	case time.Duration:
+		return gonfig.ADuration
+	}
+	return gonfig.AString
+}
+
+func apply(g gonfig.Configer, code, val string, ak gonfig.AKind, ow bool) error {
+	if p, ok := g.Get(code); ok {
+		if !ow {
+			return nil
+		}
+		ak = p.Kind()
+	}
+
+	p, err := g.Param(code, ak)
+	if err != nil {
+		return err
+	}
+	return p.Parse(val)
+}
+
+// defaults returns values of tag "default" of params if g implements
+// gonfig.Describer.
+func defaults(g gonfig.Configer) map[string]string {
+	d, ok := g.(gonfig.Describer)
+	if !ok {
+		return nil
+	}
+	res := make(map[string]string)
+	d.WalkInfo(func(v gonfig.Valuer, info gonfig.ParamInfo) {
+		if info.Default != "" {
+			res[info.Code] = info.Default
+		}
+	})
+	return res
+}
+
+// PrintHelp writes all params in container ordered by code with their
+// kinds, current and default values. ApplyTo takes defaults from
+// gonfig.ParamInfo.Default, they can be nil.
+func PrintHelp(w io.Writer, g gonfig.Configer, defaults map[string]string) {
+
+	type line struct {
+		code, kind, val string
+	}
+
+	var lines []line
+	g.Walk(func(code string, v gonfig.Valuer, inited, asked int) {
+		lines = append(lines, line{
+			code: code,
+			kind: strings.ToLower(strings.TrimPrefix(v.Kind().String(), "A")),
+			val:  fmt.Sprint(v),
+		})
+	})
+
+	sort.Slice(lines, func(i, j int) bool {
+		return lines[i].code < lines[j].code
+	})
+
+	fmt.Fprintln(w, "Usage:")
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	for _, l := range lines {
+		fmt.Fprintf(tw, "  --%s\t%s\tvalue: %q", l.code, l.kind, l.val)
+		if def, ok := defaults[l.code]; ok && def != l.val {
+			fmt.Fprintf(tw, " (default: %q)", def)
+		}
+		fmt.Fprintln(tw)
+	}
+	tw.Flush()
+}
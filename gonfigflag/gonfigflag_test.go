@@ -0,0 +1,164 @@
+package gonfigflag_test
+
+import (
+	"bytes"
+	"flag"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/axkit/gonfig"
+	"github.com/axkit/gonfig/gonfigflag"
+)
+
+func TestFlagSource_ApplyTo(t *testing.T) {
+
+	cfg := gonfig.New()
+	cfg.MustParam("port", gonfig.AInt).Parse("80")
+	cfg.MustParam("debug", gonfig.ABool).Parse("false")
+	cfg.MustParam("cache", gonfig.ABool).Parse("true")
+	cfg.MustParam("timeout", gonfig.ADuration).Parse("1s")
+
+	args := []string{"--port=8080", "--debug", "--no-cache", "-timeout", "5s", "--name", "api", "run", "--verbose", "--", "--port=1"}
+	src := gonfigflag.NewFlagSource(args)
+	if err := src.ApplyTo(cfg, true); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{
+		"port":    "8080",
+		"debug":   "true",
+		"cache":   "false",
+		"timeout": "5s",
+		"name":    "api",
+		"verbose": "true",
+	}
+
+	for code, val := range expected {
+		v, ok := cfg.Get(code)
+		if !ok {
+			t.Errorf("param '%s' not found", code)
+			continue
+		}
+		if s := v.(interface{ String() string }).String(); s != val {
+			t.Errorf("param '%s': expected %s, got %s", code, val, s)
+		}
+	}
+
+	if v, _ := cfg.Get("verbose"); v.Kind() != gonfig.ABool {
+		t.Error("flag without value must be ABool")
+	}
+
+	if rest := src.Args(); len(rest) != 2 || rest[0] != "run" || rest[1] != "--port=1" {
+		t.Errorf("wrong positional args: %v", rest)
+	}
+
+	if err := gonfigflag.NewFlagSource([]string{"--port=abc"}).ApplyTo(cfg, true); err == nil {
+		t.Error("error expected")
+	}
+
+	if err := gonfigflag.NewFlagSource([]string{"--port"}).ApplyTo(cfg, true); err == nil {
+		t.Error("error expected")
+	}
+
+	if err := gonfigflag.NewFlagSource([]string{"--port=1"}).ApplyTo(cfg, false); err != nil {
+		t.Error(err)
+	}
+	if v, _ := cfg.Get("port"); v.(*gonfig.Int).Val() != 8080 {
+		t.Error("value overwritten while ow is false")
+	}
+}
+
+func TestFlagSource_Help(t *testing.T) {
+
+	var c struct {
+		Port   gonfig.Int    `cfg:"port" default:"80"`
+		Listen gonfig.String `cfg:"listen"`
+	}
+	cfg := gonfig.New()
+	cfg.BindStruct(&c)
+	cfg.MustParam("port", gonfig.AInt).Parse("8080")
+
+	var buf bytes.Buffer
+	src := gonfigflag.NewFlagSource([]string{"--port", "9090", "-h"})
+	src.SetOutput(&buf)
+
+	if err := src.ApplyTo(cfg, true); err != gonfigflag.ErrHelp {
+		t.Errorf("expected ErrHelp, got %v", err)
+	}
+
+	out := buf.String()
+	if !strings.Contains(out, "--listen") || !strings.Contains(out, `value: "8080" (default: "80")`) {
+		t.Errorf("unexpected help output:\n%s", out)
+	}
+
+	// nothing is applied if help is requested.
+	if c.Port.Val() != 8080 {
+		t.Errorf("expected 8080, got %d", c.Port.Val())
+	}
+}
+
+func TestFlagSource_Negative(t *testing.T) {
+
+	cfg := gonfig.New()
+	cfg.MustParam("n", gonfig.AInt)
+	cfg.MustParam("ratio", gonfig.AFloat)
+	cfg.MustParam("name", gonfig.AString)
+
+	args := []string{"--n", "-5", "--ratio", "-.5", "--name", "-x"}
+	err := gonfigflag.NewFlagSource(args).ApplyTo(cfg, true)
+	if n, _ := cfg.Get("n"); n.(*gonfig.Int).Val() != -5 {
+		t.Errorf("expected -5, got %d", n.(*gonfig.Int).Val())
+	}
+	if r, _ := cfg.Get("ratio"); r.(*gonfig.Float).Val() != -0.5 {
+		t.Errorf("expected -0.5, got %v", r.(*gonfig.Float).Val())
+	}
+
+	// value of string param starting with dash is the next flag.
+	if err == nil || !strings.Contains(err.Error(), "--name") {
+		t.Errorf("expected error of --name, got %v", err)
+	}
+}
+
+func TestFlagSetSource_ApplyTo(t *testing.T) {
+
+	fs := flag.NewFlagSet("app", flag.ContinueOnError)
+	fs.Int("port", 80, "listening port")
+	fs.Bool("debug", false, "debug mode")
+	fs.Duration("timeout", time.Second, "timeout")
+	fs.String("name", "app", "name")
+
+	src := gonfigflag.NewFlagSetSource(fs)
+
+	cfg := gonfig.New()
+	if err := src.ApplyTo(cfg, true); err != gonfigflag.ErrNotParsed {
+		t.Errorf("expected ErrNotParsed, got %v", err)
+	}
+
+	cfg.MustParam("name", gonfig.AString).Parse("from-file")
+	cfg.MustParam("debug", gonfig.ABool).Parse("false")
+
+	if err := fs.Parse([]string{"-port=8080", "-debug"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := src.ApplyTo(cfg, true); err != nil {
+		t.Fatal(err)
+	}
+
+	if v, _ := cfg.Get("port"); v.Kind() != gonfig.AInt || v.(*gonfig.Int).Val() != 8080 {
+		t.Error("port is not applied")
+	}
+
+	if v, _ := cfg.Get("timeout"); v.Kind() != gonfig.ADuration || v.(*gonfig.Duration).Val() != time.Second {
+		t.Error("default of timeout is not applied")
+	}
+
+	if v, _ := cfg.Get("debug"); !v.(*gonfig.Bool).Val() {
+		t.Error("debug is not applied")
+	}
+
+	if v, _ := cfg.Get("name"); v.(*gonfig.String).Val() != "from-file" {
+		t.Error("flag default must not overwrite existing value")
+	}
+}
@@ -0,0 +1,347 @@
+// Package gonfighttp implements http.Handler for viewing and
+// changing parameters of config container in runtime.
+//
+// Changes are attributed to source "http". Middleware can put actor
+// into request context by gonfig.WithActor to make it audited.
+package gonfighttp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/axkit/gonfig"
+)
+
+// Mask replaces values of masked params.
+const Mask = "******"
+
+// maxBodySize limits size of request body.
+const maxBodySize = 1 << 20
+
+// AuthorizeFunc returns true if request r is allowed. write is true
+// for requests changing values. code is empty for requests
+// related to all params.
+type AuthorizeFunc func(r *http.Request, code string, write bool) bool
+
+// MaskFunc returns true if value of param identified by code
+// must not be shown.
+type MaskFunc func(code string) bool
+
+// DefaultMask masks params having password, secret, token or key
+// in the code.
+func DefaultMask(code string) bool {
+	code = strings.ToLower(code)
+	for _, s := range []string{"password", "passwd", "secret", "token", "key"} {
+		if strings.Contains(code, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// Param is a param representation in responses.
+type Param struct {
+	Code   string `json:"code"`
+	Kind   string `json:"kind"`
+	Value  string `json:"value"`
+	Masked bool   `json:"masked,omitempty"`
+	Inited int    `json:"inited"`
+	Asked  int    `json:"asked"`
+	Origin string `json:"origin,omitempty"`
+
+	Desc   string `json:"desc,omitempty"`
+	Unit   string `json:"unit,omitempty"`
+	Group  string `json:"group,omitempty"`
+	Static bool   `json:"static,omitempty"`
+
+	// Pending is a value of static param applied after restart.
+	Pending string `json:"pending,omitempty"`
+}
+
+// Handler serves list of params as JSON or HTML and changes values.
+//
+//	GET    /        list of params, HTML if requested by Accept header
+//	                or ?format=html
+//	GET    /{code}  single param
+//	PUT    /{code}  change value, body is raw value or {"value": "..."}
+//	PATCH  /{code}  the same as PUT
+//	PATCH  /        change several values together, body is
+//	                {"code": "value", ...}
+//
+// Handler is expected to be mounted with http.StripPrefix.
+type Handler struct {
+	cfg       Config
+	readOnly  bool
+	authorize AuthorizeFunc
+	mask      MaskFunc
+}
+
+// Config is a container served by Handler. It's implemented by
+// *gonfig.Config and views returned by its Sub method.
+type Config interface {
+	gonfig.Describer
+	gonfig.Originer
+	gonfig.Updater
+}
+
+// NewHandler returns Handler serving params of cfg. All requests
+// are allowed, values are masked by DefaultMask.
+func NewHandler(cfg Config) *Handler {
+	return &Handler{cfg: cfg, mask: DefaultMask}
+}
+
+// WithReadOnly forbids changing values.
+func (h *Handler) WithReadOnly() *Handler {
+	h.readOnly = true
+	return h
+}
+
+// WithAuthorizer sets function authorizing requests.
+func (h *Handler) WithAuthorizer(f AuthorizeFunc) *Handler {
+	h.authorize = f
+	return h
+}
+
+// WithMask sets function selecting params which values must be
+// masked. nil disables masking. Values of ASecret params
+// are always masked.
+func (h *Handler) WithMask(f MaskFunc) *Handler {
+	h.mask = f
+	return h
+}
+
+// ServeHTTP implements http.Handler interface.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	code := strings.Trim(r.URL.Path, "/")
+
+	write := r.Method == http.MethodPut || r.Method == http.MethodPatch
+	if !write && r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD, PUT, PATCH")
+		httpError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	if write && h.readOnly {
+		httpError(w, http.StatusMethodNotAllowed, "read only")
+		return
+	}
+
+	if h.authorize != nil && !h.authorize(r, code, write) {
+		httpError(w, http.StatusForbidden, "forbidden")
+		return
+	}
+
+	switch {
+	case !write && code == "":
+		h.list(w, r)
+	case !write:
+		h.get(w, code)
+	case code == "":
+		if r.Method != http.MethodPatch {
+			w.Header().Set("Allow", "GET, HEAD, PATCH")
+			httpError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		h.patch(w, r)
+	default:
+		h.put(w, r, code)
+	}
+}
+
+func (h *Handler) params() []Param {
+	var res []Param
+	h.cfg.WalkInfo(func(v gonfig.Valuer, info gonfig.ParamInfo) {
+		res = append(res, h.param(v, info))
+	})
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Code < res[j].Code
+	})
+
+	// origins are requested outside of Walk holding the container lock.
+	for i := range res {
+		if o, ok := h.cfg.Origin(res[i].Code); ok {
+			res[i].Origin = o.String()
+		}
+	}
+	return res
+}
+
+func (h *Handler) param(v gonfig.Valuer, info gonfig.ParamInfo) Param {
+	p := Param{
+		Code:    info.Code,
+		Kind:    v.Kind().String(),
+		Value:   fmt.Sprint(v),
+		Inited:  info.Inited,
+		Asked:   info.Asked,
+		Desc:    info.Desc,
+		Unit:    info.Unit,
+		Group:   info.Group,
+		Static:  info.Static,
+		Pending: info.Pending,
+	}
+	if v.Kind() == gonfig.ASecret || h.mask != nil && h.mask(info.Code) {
+		p.Value, p.Masked = Mask, true
+		if p.Pending != "" {
+			p.Pending = Mask
+		}
+	}
+	return p
+}
+
+func (h *Handler) list(w http.ResponseWriter, r *http.Request) {
+	params := h.params()
+	if r.URL.Query().Get("format") == "html" || strings.Contains(r.Header.Get("Accept"), "text/html") {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		if err := page.Execute(w, struct {
+			Params   []Param
+			ReadOnly bool
+		}{params, h.readOnly}); err != nil {
+			httpError(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+	writeJSON(w, http.StatusOK, params)
+}
+
+func (h *Handler) get(w http.ResponseWriter, code string) {
+	p, ok := h.find(code)
+	if !ok {
+		httpError(w, http.StatusNotFound, "param not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, p)
+}
+
+func (h *Handler) find(code string) (Param, bool) {
+	var res Param
+	var found bool
+	h.cfg.WalkInfo(func(v gonfig.Valuer, info gonfig.ParamInfo) {
+		if info.Code == code {
+			res, found = h.param(v, info), true
+		}
+	})
+	if found {
+		if o, ok := h.cfg.Origin(code); ok {
+			res.Origin = o.String()
+		}
+	}
+	return res, found
+}
+
+// put changes single param. Body is raw value or JSON object
+// with attribute value.
+func (h *Handler) put(w http.ResponseWriter, r *http.Request, code string) {
+	buf, err := io.ReadAll(io.LimitReader(r.Body, maxBodySize))
+	if err != nil {
+		httpError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	val := string(buf)
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
+		var req struct {
+			Value *string `json:"value"`
+		}
+		if err := json.Unmarshal(buf, &req); err != nil || req.Value == nil {
+			httpError(w, http.StatusBadRequest, "expected {\"value\": \"...\"}")
+			return
+		}
+		val = *req.Value
+	}
+
+	h.update(w, r, map[string]string{code: val}, code)
+}
+
+// patch changes several params together.
+func (h *Handler) patch(w http.ResponseWriter, r *http.Request) {
+	var req map[string]string
+	if err := json.NewDecoder(io.LimitReader(r.Body, maxBodySize)).Decode(&req); err != nil {
+		httpError(w, http.StatusBadRequest, "expected {\"code\": \"value\", ...}")
+		return
+	}
+
+	if h.authorize != nil {
+		for code := range req {
+			if !h.authorize(r, code, true) {
+				httpError(w, http.StatusForbidden, "forbidden: "+code)
+				return
+			}
+		}
+	}
+	h.update(w, r, req, "")
+}
+
+// update changes params with source "http". Actor can be put
+// into request context by gonfig.WithActor.
+func (h *Handler) update(w http.ResponseWriter, r *http.Request, vals map[string]string, code string) {
+	codes := make([]string, 0, len(vals))
+	for c := range vals {
+		codes = append(codes, c)
+	}
+	sort.Strings(codes)
+
+	ctx := gonfig.WithSource(r.Context(), "http")
+	err := h.cfg.UpdateContext(ctx, func(tx gonfig.Tx) error {
+		for _, c := range codes {
+			if err := tx.Parse(c, vals[c]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	switch {
+	case errors.Is(err, gonfig.ErrNotFound):
+		httpError(w, http.StatusNotFound, err.Error())
+		return
+	case errors.Is(err, gonfig.ErrStatic):
+		httpError(w, http.StatusConflict, err.Error())
+		return
+	case err != nil:
+		var ve *gonfig.ValidationError
+		if errors.As(err, &ve) && h.mask != nil && h.mask(ve.Code) {
+			masked := *ve
+			masked.Value = Mask
+			err = &masked
+		}
+		httpError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if code != "" {
+		h.get(w, code)
+		return
+	}
+	writeJSON(w, http.StatusOK, h.params())
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func httpError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, struct {
+		Error string `json:"error"`
+	}{msg})
+}
+
+var page = template.Must(template.New("page").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>Parameters</title></head>
+<body>
+<table border="1" cellpadding="4" cellspacing="0">
+<tr><th>Code</th><th>Kind</th><th>Value</th><th>Inited</th><th>Asked</th><th>Origin</th><th>Description</th></tr>
+{{range .Params}}<tr><td>{{.Code}}</td><td>{{.Kind}}</td><td>{{.Value}}{{if .Unit}} {{.Unit}}{{end}}{{if .Pending}} (pending restart: {{.Pending}}){{end}}</td><td>{{.Inited}}</td><td>{{.Asked}}</td><td>{{.Origin}}</td><td>{{.Desc}}</td></tr>
+{{end}}</table>
+{{if .ReadOnly}}<p>Read only.</p>{{end}}
+</body>
+</html>
+`))
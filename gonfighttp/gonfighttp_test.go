@@ -0,0 +1,188 @@
+package gonfighttp_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/axkit/gonfig"
+	"github.com/axkit/gonfig/gonfighttp"
+)
+
+type server struct {
+	Port     gonfig.Int    `cfg:"port" default:"8080" min:"1"`
+	Listen   gonfig.String `cfg:"listen" default:"localhost"`
+	Password gonfig.String `cfg:"db_password" default:"qwerty"`
+	APIKey   gonfig.Secret `cfg:"api" default:"abc123"`
+}
+
+func newConfig() (*gonfig.Config, *server) {
+	var s server
+	cfg := gonfig.New()
+	cfg.BindStruct(&s)
+	return cfg, &s
+}
+
+func do(h http.Handler, method, path, ctype, body string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(method, path, strings.NewReader(body))
+	if ctype != "" {
+		r.Header.Set("Content-Type", ctype)
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	return w
+}
+
+func TestHandler_List(t *testing.T) {
+
+	cfg, _ := newConfig()
+	h := gonfighttp.NewHandler(cfg)
+
+	w := do(h, http.MethodGet, "/", "", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("unexpected status %d", w.Code)
+	}
+
+	var params []gonfighttp.Param
+	if err := json.Unmarshal(w.Body.Bytes(), &params); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(params) != 4 || params[0].Code != "api" || params[1].Code != "db_password" || params[3].Code != "port" {
+		t.Fatalf("unexpected params: %+v", params)
+	}
+
+	if params[0].Value != gonfighttp.Mask || !params[0].Masked {
+		t.Error("secret is not masked")
+	}
+
+	if params[1].Value != gonfighttp.Mask || !params[1].Masked {
+		t.Error("password is not masked")
+	}
+
+	if params[3].Value != "8080" || params[3].Kind != "AInt" || params[3].Asked != 1 {
+		t.Errorf("unexpected param: %+v", params[3])
+	}
+
+	w = do(h, http.MethodGet, "/?format=html", "", "")
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/html") {
+		t.Errorf("unexpected content type %s", ct)
+	}
+	if body := w.Body.String(); strings.Contains(body, "qwerty") || !strings.Contains(body, "<td>port</td>") {
+		t.Errorf("unexpected html:\n%s", body)
+	}
+
+	if w := do(h, http.MethodGet, "/unknown", "", ""); w.Code != http.StatusNotFound {
+		t.Errorf("expected 404, got %d", w.Code)
+	}
+
+	if w := do(h, http.MethodDelete, "/port", "", ""); w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected 405, got %d", w.Code)
+	}
+}
+
+func TestHandler_Update(t *testing.T) {
+
+	cfg, s := newConfig()
+	h := gonfighttp.NewHandler(cfg)
+
+	w := do(h, http.MethodPut, "/port", "text/plain", "9090")
+	if w.Code != http.StatusOK || s.Port.Val() != 9090 {
+		t.Errorf("PUT failed: %d %s", w.Code, w.Body.String())
+	}
+
+	w = do(h, http.MethodPatch, "/port", "application/json", `{"value": "9091"}`)
+	if w.Code != http.StatusOK || s.Port.Val() != 9091 {
+		t.Errorf("PATCH failed: %d %s", w.Code, w.Body.String())
+	}
+
+	if w := do(h, http.MethodPut, "/port", "text/plain", "-1"); w.Code != http.StatusBadRequest || s.Port.Val() != 9091 {
+		t.Errorf("invalid value must be rejected: %d", w.Code)
+	}
+
+	if w := do(h, http.MethodPut, "/unknown", "text/plain", "1"); w.Code != http.StatusNotFound {
+		t.Errorf("expected 404, got %d", w.Code)
+	}
+
+	w = do(h, http.MethodPatch, "/", "application/json", `{"port": "80", "listen": "0.0.0.0"}`)
+	if w.Code != http.StatusOK || s.Port.Val() != 80 || s.Listen.Val() != "0.0.0.0" {
+		t.Errorf("PATCH / failed: %d %s", w.Code, w.Body.String())
+	}
+
+	w = do(h, http.MethodPatch, "/", "application/json", `{"port": "0", "listen": "127.0.0.1"}`)
+	if w.Code != http.StatusBadRequest || s.Listen.Val() != "0.0.0.0" {
+		t.Errorf("PATCH / must be atomic: %d %s", w.Code, w.Body.String())
+	}
+
+	// rejected values of masked params are not echoed.
+	var tok struct {
+		Token gonfig.String `cfg:"token" min:"8"`
+	}
+	cfg.BindStruct(&tok)
+	w = do(h, http.MethodPut, "/token", "text/plain", "short")
+	if w.Code != http.StatusBadRequest || strings.Contains(w.Body.String(), "short") {
+		t.Errorf("unexpected response: %d %s", w.Code, w.Body.String())
+	}
+}
+
+func TestHandler_Access(t *testing.T) {
+
+	cfg, s := newConfig()
+
+	h := gonfighttp.NewHandler(cfg).WithReadOnly()
+	if w := do(h, http.MethodPut, "/port", "", "1"); w.Code != http.StatusMethodNotAllowed || s.Port.Val() != 8080 {
+		t.Errorf("read only handler changed value: %d", w.Code)
+	}
+
+	h = gonfighttp.NewHandler(cfg).
+		WithMask(nil).
+		WithAuthorizer(func(r *http.Request, code string, write bool) bool {
+			return !write || code == "listen"
+		})
+
+	if w := do(h, http.MethodGet, "/db_password", "", ""); !strings.Contains(w.Body.String(), "qwerty") {
+		t.Error("mask must be disabled")
+	}
+
+	if w := do(h, http.MethodGet, "/api", "", ""); strings.Contains(w.Body.String(), "abc123") {
+		t.Error("secret must be masked always")
+	}
+
+	if w := do(h, http.MethodPut, "/port", "", "1"); w.Code != http.StatusForbidden {
+		t.Errorf("expected 403, got %d", w.Code)
+	}
+
+	if w := do(h, http.MethodPatch, "/", "application/json", `{"listen": "a", "port": "1"}`); w.Code != http.StatusForbidden {
+		t.Errorf("expected 403, got %d", w.Code)
+	}
+
+	if w := do(h, http.MethodPut, "/listen", "", "a"); w.Code != http.StatusOK || s.Listen.Val() != "a" {
+		t.Errorf("PUT failed: %d", w.Code)
+	}
+}
+
+func TestHandler_Static(t *testing.T) {
+
+	var s struct {
+		Workers gonfig.Int `cfg:"workers" default:"4" static:"true" desc:"Number of workers"`
+	}
+	cfg := gonfig.New()
+	cfg.BindStruct(&s)
+	cfg.Freeze()
+	h := gonfighttp.NewHandler(cfg)
+
+	if w := do(h, http.MethodPut, "/workers", "", "8"); w.Code != http.StatusConflict {
+		t.Errorf("unexpected status %d: %s", w.Code, w.Body)
+	}
+
+	w := do(h, http.MethodGet, "/workers", "", "")
+	var p gonfighttp.Param
+	if err := json.Unmarshal(w.Body.Bytes(), &p); err != nil {
+		t.Fatal(err)
+	}
+	if p.Value != "4" || !p.Static || p.Pending != "8" || p.Desc != "Number of workers" {
+		t.Errorf("unexpected param %+v", p)
+	}
+}
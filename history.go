@@ -0,0 +1,175 @@
+package gonfig
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrNoHistory raises when Rollback is called without history
+// or with more changes than recorded.
+var ErrNoHistory = errors.New("not enough change history")
+
+// DefaultHistorySize is a number of changes kept by WithHistory
+// if size is not positive.
+var DefaultHistorySize = 100
+
+type record struct {
+	seq uint64
+	ak  AKind
+	Change
+}
+
+// history keeps the last changes of all params in container.
+type history struct {
+	mux     sync.Mutex
+	size    int
+	seq     uint64
+	records []record
+}
+
+// WithHistory turns on recording of the last size changes of all
+// params. Recorded changes are returned by History and reverted
+// by Rollback. Secrets are recorded as well, History masks them.
+func (c *Config) WithHistory(size int) *Config {
+	if size <= 0 {
+		size = DefaultHistorySize
+	}
+
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	if h := c.hist; h != nil {
+		h.mux.Lock()
+		h.resize(size)
+		h.mux.Unlock()
+		return c
+	}
+
+	c.hist = &history{size: size}
+	for i := range c.list {
+		c.hist.watch(c, c.list[i].code, c.list[i].av)
+	}
+	return c
+}
+
+func (h *history) watch(c *Config, code string, v Valuer) {
+	ak := v.Kind()
+	c.subscribeWith(code, v.(watchable).mem(), &subscription{
+		record: func(c Change, _ time.Time, by *author) { h.record(ak, c, by) },
+		plain:  true,
+	})
+}
+
+func (h *history) record(ak AKind, c Change, by *author) {
+	h.mux.Lock()
+	defer h.mux.Unlock()
+
+	if by != nil && by.rollback {
+		return
+	}
+
+	h.seq++
+	h.records = append(h.records, record{seq: h.seq, ak: ak, Change: c})
+	h.resize(h.size)
+}
+
+// resize drops the oldest records exceeding size.
+// Must be called with locked mux.
+func (h *history) resize(size int) {
+	h.size = size
+	if n := len(h.records) - size; n > 0 {
+		h.records = append(h.records[:0:0], h.records[n:]...)
+	}
+}
+
+// History returns recorded changes from the oldest to the latest.
+// Values of secrets are masked. Returns nil if history is not
+// turned on by WithHistory.
+func (c *Config) History() []Change {
+	c.mux.RLock()
+	h := c.hist
+	c.mux.RUnlock()
+	if h == nil {
+		return nil
+	}
+
+	h.mux.Lock()
+	defer h.mux.Unlock()
+
+	res := make([]Change, len(h.records))
+	for i, r := range h.records {
+		res[i] = r.Change
+		if r.ak == ASecret {
+			res[i].Old, res[i].New = mask(r.Old), mask(r.New)
+		}
+	}
+	return res
+}
+
+// Rollback reverts the last n recorded changes. Values are restored
+// together as by Update and reverted changes are removed from
+// history. Returns ErrNoHistory if fewer than n changes recorded.
+func (c *Config) Rollback(n int) error {
+	c.mux.RLock()
+	h := c.hist
+	c.mux.RUnlock()
+
+	if h == nil || n < 0 {
+		return ErrNoHistory
+	}
+	if n == 0 {
+		return nil
+	}
+
+	h.mux.Lock()
+	if n > len(h.records) {
+		h.mux.Unlock()
+		return ErrNoHistory
+	}
+
+	last := h.records[len(h.records)-n:]
+	seqs := make(map[uint64]bool, n)
+	target := make(map[string]record)
+	var codes []string
+	for i := len(last) - 1; i >= 0; i-- {
+		r := last[i]
+		seqs[r.seq] = true
+		if _, ok := target[r.Code]; !ok {
+			codes = append(codes, r.Code)
+		}
+		target[r.Code] = r
+	}
+
+	h.mux.Unlock()
+
+	err := c.update(&author{source: "rollback", rollback: true}, func(tx Tx) error {
+		for _, code := range codes {
+			r := target[code]
+			s := r.Old
+			if r.ak == AString || r.ak == ASecret {
+				s = escape(s)
+			}
+			if err := tx.Parse(code, s); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	h.mux.Lock()
+	defer h.mux.Unlock()
+
+	if err != nil {
+		return err
+	}
+
+	records := h.records[:0:0]
+	for _, r := range h.records {
+		if !seqs[r.seq] {
+			records = append(records, r)
+		}
+	}
+	h.records = records
+	return nil
+}
@@ -0,0 +1,200 @@
+package gonfig
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"unsafe"
+)
+
+// ErrStatic raises when static param is changed after Freeze.
+var ErrStatic = errors.New("static param, restart required")
+
+// ParamInfo describes param. Desc, Unit, Group, Default and Static
+// are read by BindStruct from tags desc, unit, group, default
+// and static.
+type ParamInfo struct {
+	Code    string
+	Kind    AKind
+	Default string
+	Desc    string
+	Unit    string
+	Group   string
+
+	// Static params can't be changed after Freeze.
+	Static bool
+
+	// Pending is a value of static param rejected after Freeze.
+	// It's expected to be applied after restart. Secrets are masked.
+	Pending string
+
+	Inited int
+	Asked  int
+}
+
+// meta holds param metadata read from struct tags.
+type meta struct {
+	def    string
+	desc   string
+	unit   string
+	group  string
+	static *static
+}
+
+// static rejects changes of a static param after Freeze.
+type static struct {
+	p      Valuer
+	frozen int32
+
+	mux     sync.Mutex
+	pending string
+}
+
+// check allows value v if param is not frozen or v equals
+// current value. Rejected value becomes pending.
+func (s *static) check(v Valuer) bool {
+	if atomic.LoadInt32(&s.frozen) == 0 {
+		return true
+	}
+
+	same := equal(s.p, v)
+
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	if same {
+		s.pending = ""
+		return true
+	}
+	s.pending = fmt.Sprint(v)
+	return false
+}
+
+func (s *static) pendingValue() string {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	return s.pending
+}
+
+// rejected returns true if shared memory mem belongs to static param
+// frozen by Freeze and value returned by v differs from its value.
+// v is called only if param has validation rules. c can be nil.
+func (c *Config) rejected(mem unsafe.Pointer, v func() Valuer) bool {
+	vr := c.validatorOf(mem)
+	if vr == nil {
+		return false
+	}
+
+	vr.mux.RLock()
+	defer vr.mux.RUnlock()
+	for _, r := range vr.rules {
+		if r.name == "static" {
+			return !r.check(v())
+		}
+	}
+	return false
+}
+
+// equal returns true if values a and b are equal.
+func equal(a, b Valuer) bool {
+	x, err := exact(a)
+	if err != nil {
+		return false
+	}
+	y, err := exact(b)
+	return err == nil && x == y
+}
+
+// setMeta reads metadata from tag values. Empty values
+// don't overwrite existing ones. Must be called with locked c.mux.
+func (c *Config) setMeta(idx int, def, desc, unit, group, st string) error {
+	p := &c.list[idx]
+	if def != "" {
+		p.meta.def = def
+	}
+	if desc != "" {
+		p.meta.desc = desc
+	}
+	if unit != "" {
+		p.meta.unit = unit
+	}
+	if group != "" {
+		p.meta.group = group
+	}
+
+	if st == "" {
+		return nil
+	}
+	isStatic, err := strconv.ParseBool(st)
+	if err != nil {
+		return fmt.Errorf("param '%s': invalid tag static: %w", p.code, err)
+	}
+	if !isStatic || p.meta.static != nil {
+		return nil
+	}
+
+	s := &static{p: p.av}
+	if c.frozen {
+		s.frozen = 1
+	}
+	p.meta.static = s
+	c.validatorFor(p.code, p.av.(watchable).mem()).add(rule{name: "static", check: s.check})
+	return nil
+}
+
+// Info returns description of param identified by code.
+func (c *Config) Info(code string) (ParamInfo, bool) {
+	c.mux.RLock()
+	defer c.mux.RUnlock()
+	idx, ok := c.idx[code]
+	if !ok {
+		return ParamInfo{}, false
+	}
+	return c.list[idx].info(), true
+}
+
+// WalkInfo calls function f() for every parameter in container.
+func (c *Config) WalkInfo(f func(v Valuer, info ParamInfo)) {
+	c.mux.RLock()
+	defer c.mux.RUnlock()
+	for i := range c.list {
+		f(c.list[i].av, c.list[i].info())
+	}
+}
+
+func (p *param) info() ParamInfo {
+	res := ParamInfo{
+		Code:    p.code,
+		Kind:    p.av.Kind(),
+		Default: p.meta.def,
+		Desc:    p.meta.desc,
+		Unit:    p.meta.unit,
+		Group:   p.meta.group,
+		Static:  p.meta.static != nil,
+		Inited:  p.inited,
+		Asked:   p.asked,
+	}
+	if p.meta.static != nil {
+		res.Pending = p.meta.static.pendingValue()
+	}
+	return res
+}
+
+// Freeze makes static params reject changes by Parse with error
+// wrapping ErrStatic. Rejected value is reported by Info as pending.
+// Params bound as static after Freeze are frozen immediately.
+//
+// Like other validation rules it covers Parse, Update and sources
+// applied by Loader or watchers. Set of a variable has no error to
+// return, rejected value is ignored and reported as pending.
+func (c *Config) Freeze() {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	c.frozen = true
+	for i := range c.list {
+		if s := c.list[i].meta.static; s != nil {
+			atomic.StoreInt32(&s.frozen, 1)
+		}
+	}
+}
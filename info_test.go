@@ -0,0 +1,129 @@
+package gonfig_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/axkit/gonfig"
+)
+
+type infoConfig struct {
+	Pool    gonfig.Int    `cfg:"db.pool" default:"10" desc:"Pool size" group:"db" static:"true"`
+	Timeout gonfig.Int    `cfg:"timeout" default:"500" unit:"ms" desc:"Request timeout"`
+	Pass    gonfig.Secret `cfg:"db.password" static:"true"`
+}
+
+func TestConfig_Info(t *testing.T) {
+
+	var c infoConfig
+	cfg := gonfig.New()
+	if errs := cfg.BindStruct(&c); len(errs) > 0 {
+		t.Fatal(errs)
+	}
+
+	info, ok := cfg.Info("db.pool")
+	if !ok {
+		t.Fatal("expected info")
+	}
+	if info.Code != "db.pool" || info.Kind != gonfig.AInt || info.Default != "10" || info.Desc != "Pool size" ||
+		info.Group != "db" || !info.Static || info.Asked != 1 {
+		t.Errorf("unexpected info %+v", info)
+	}
+
+	if info, _ := cfg.Info("timeout"); info.Unit != "ms" || info.Static {
+		t.Errorf("unexpected info %+v", info)
+	}
+
+	if _, ok := cfg.Info("unknown"); ok {
+		t.Error("unexpected info")
+	}
+
+	n := 0
+	cfg.WalkInfo(func(v gonfig.Valuer, info gonfig.ParamInfo) {
+		if info.Code == "db.pool" && v.(*gonfig.Int).Val() == 10 {
+			n++
+		}
+	})
+	if n != 1 {
+		t.Error("expected db.pool in WalkInfo")
+	}
+
+	var bad struct {
+		X gonfig.Int `cfg:"x" static:"yes"`
+	}
+	if errs := cfg.BindStruct(&bad); len(errs) != 1 {
+		t.Errorf("expected error, got %v", errs)
+	}
+}
+
+func TestConfig_Freeze(t *testing.T) {
+
+	var c infoConfig
+	cfg := gonfig.New()
+	cfg.BindStruct(&c)
+	cfg.MustParam("db.password", gonfig.ASecret).Parse("qwerty")
+
+	// static params can be changed before Freeze.
+	c.Pool.Parse("20")
+	cfg.Freeze()
+
+	p := cfg.MustParam("db.pool", gonfig.AInt)
+	if err := p.Parse("30"); !errors.Is(err, gonfig.ErrStatic) {
+		t.Errorf("expected ErrStatic, got %v", err)
+	}
+	if c.Pool.Val() != 20 {
+		t.Errorf("expected 20, got %d", c.Pool.Val())
+	}
+	if info, _ := cfg.Info("db.pool"); info.Pending != "30" {
+		t.Errorf("expected pending 30, got %q", info.Pending)
+	}
+
+	// the same value is accepted and clears pending.
+	if err := p.Parse("20"); err != nil {
+		t.Error(err)
+	}
+	if info, _ := cfg.Info("db.pool"); info.Pending != "" {
+		t.Errorf("expected no pending, got %q", info.Pending)
+	}
+
+	// dynamic params are not affected.
+	if err := c.Timeout.Parse("100"); err != nil {
+		t.Error(err)
+	}
+
+	if err := cfg.Update(func(tx gonfig.Tx) error {
+		return tx.Parse("db.password", "new")
+	}); !errors.Is(err, gonfig.ErrStatic) {
+		t.Errorf("expected ErrStatic, got %v", err)
+	}
+	if info, _ := cfg.Info("db.password"); info.Pending != gonfig.SecretMask {
+		t.Errorf("expected masked pending, got %q", info.Pending)
+	}
+
+	// loader skips static params keeping them pending.
+	l := gonfig.NewLoader().WithLogf(nil).Add("file", gonfig.MapSource{"db.pool": "40", "timeout": "200"})
+	if err := l.Load(cfg); err != nil {
+		t.Fatal(err)
+	}
+	if c.Pool.Val() != 20 || c.Timeout.Val() != 200 {
+		t.Errorf("unexpected values %d %d", c.Pool.Val(), c.Timeout.Val())
+	}
+	if info, _ := cfg.Info("db.pool"); info.Pending != "40" {
+		t.Errorf("expected pending 40, got %q", info.Pending)
+	}
+	if _, ok := cfg.Origin("db.pool"); ok {
+		t.Error("origin of static param is not expected")
+	}
+	// Set is rejected as well.
+	c.Pool.Set(50)
+	if c.Pool.Val() != 20 {
+		t.Errorf("expected 20, got %d", c.Pool.Val())
+	}
+	if info, _ := cfg.Info("db.pool"); info.Pending != "50" {
+		t.Errorf("expected pending 50, got %q", info.Pending)
+	}
+	c.Timeout.Set(300)
+	if c.Timeout.Val() != 300 {
+		t.Errorf("expected 300, got %d", c.Timeout.Val())
+	}
+}
@@ -14,11 +14,14 @@ var NonBindedInt int = 0
 // A Int implements atomic int.
 type Int struct {
 	ref *int64
+
+	// c is container the param belongs to.
+	c *Config
 }
 
 // NewInt returns atomic int implemented using int64.
 func NewInt() *Int {
-	return &Int{new(int64)}
+	return &Int{ref: new(int64)}
 }
 
 // Kind returns AInt.
@@ -28,7 +31,26 @@ func (a *Int) Kind() AKind {
 
 // Set assigns value atomically. Initializes if was not before.
 func (a *Int) Set(i int) {
+	if a.owner().rejected(a.mem(), func() Valuer {
+		n := NewInt()
+		n.Set(i)
+		return n
+	}) {
+		return
+	}
+	a.set(i, nil)
+}
+
+// set is like Set, the change is attributed to by.
+func (a *Int) set(i int, by *author) {
 	ptr := atomic.LoadPointer((*unsafe.Pointer)(unsafe.Pointer(&a.ref)))
+	if n := a.owner().notifierOf(ptr); n != nil {
+		n.change(by, func() (string, string, bool) {
+			old := atomic.SwapInt64((*int64)(ptr), int64(i))
+			return strconv.FormatInt(old, 10), strconv.Itoa(i), old != int64(i)
+		})
+		return
+	}
 	if ptr != nil {
 		atomic.StoreInt64((*int64)(ptr), int64(i))
 		return
@@ -54,11 +76,28 @@ func (a *Int) IsBinded() bool {
 	return atomic.LoadPointer((*unsafe.Pointer)(unsafe.Pointer(&a.ref))) != nil
 }
 
+func (a *Int) mem() unsafe.Pointer {
+	return atomic.LoadPointer((*unsafe.Pointer)(unsafe.Pointer(&a.ref)))
+}
+
+func (a *Int) owner() *Config {
+	return loadOwner(&a.c)
+}
+
+func (a *Int) own(c *Config) {
+	storeOwner(&a.c, c)
+}
+
+func (a *Int) assign(v Valuer, by *author) {
+	a.set(v.(*Int).Val(), by)
+}
+
 // Bind binds current atomic variable to variable identified by to.
 // As a result two Int address to the same variable.
 func (a *Int) Bind(to *Int) {
 	ptr := atomic.LoadPointer((*unsafe.Pointer)(unsafe.Pointer(&to.ref)))
 	if ptr != nil {
+		storeOwner(&a.c, to.owner())
 		atomic.StorePointer((*unsafe.Pointer)(unsafe.Pointer(&a.ref)), ptr)
 	}
 }
@@ -69,16 +108,23 @@ func (a *Int) String() string {
 }
 
 // Parse converts input argument and assigns to value.
+// Value is not changed if it violates validation rules.
 func (a *Int) Parse(s string) error {
 	s = strings.TrimSpace(s)
-	if len(s) == 0 {
-		a.Set(0)
-		return nil
+	var i int
+	if len(s) != 0 {
+		var err error
+		if i, err = strconv.Atoi(s); err != nil {
+			return err
+		}
 	}
 
-	i, err := strconv.Atoi(s)
-	if err != nil {
-		return err
+	if vr := a.owner().validatorOf(a.mem()); vr != nil {
+		n := NewInt()
+		n.Set(i)
+		if err := vr.check(n); err != nil {
+			return err
+		}
 	}
 	a.Set(i)
 	return nil
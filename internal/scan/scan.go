@@ -0,0 +1,186 @@
+// Package scan finds struct types and their tagged fields in Go
+// source files. It's used by gonfig tools working without
+// compiling the code.
+package scan
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ImportPath is an import path of package gonfig.
+const ImportPath = "github.com/axkit/gonfig"
+
+// Field is a field of struct type.
+type Field struct {
+	Name string
+
+	// Type is a type expression as written in the source.
+	Type string
+
+	// Gonfig is a name of gonfig type, for example Int or Value,
+	// if field type is declared in package gonfig.
+	Gonfig string
+
+	Tag reflect.StructTag
+
+	// Embedded is true for embedded fields.
+	Embedded bool
+}
+
+// Struct is a struct type declared at package level.
+type Struct struct {
+	Name   string
+	Fields []Field
+}
+
+// Package is a package of scanned files.
+type Package struct {
+	Name    string
+	Structs []Struct
+}
+
+// Struct returns struct type identified by name.
+func (p *Package) Struct(name string) (*Struct, bool) {
+	for i := range p.Structs {
+		if p.Structs[i].Name == name {
+			return &p.Structs[i], true
+		}
+	}
+	return nil, false
+}
+
+// Dir parses Go files of directory dir skipping tests.
+func Dir(dir string) (*Package, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		files = append(files, filepath.Join(dir, name))
+	}
+	sort.Strings(files)
+	return Files(files...)
+}
+
+// Files parses Go files of the same package.
+func Files(paths ...string) (*Package, error) {
+	fset := token.NewFileSet()
+	pkg := &Package{}
+	for _, path := range paths {
+		f, err := parser.ParseFile(fset, path, nil, parser.SkipObjectResolution)
+		if err != nil {
+			return nil, err
+		}
+		pkg.Name = f.Name.Name
+		pkg.Structs = append(pkg.Structs, structsOf(f)...)
+	}
+	return pkg, nil
+}
+
+func structsOf(f *ast.File) []Struct {
+	gonfig := ""
+	for _, imp := range f.Imports {
+		if path, _ := strconv.Unquote(imp.Path.Value); path == ImportPath {
+			gonfig = "gonfig"
+			if imp.Name != nil {
+				gonfig = imp.Name.Name
+			}
+		}
+	}
+
+	var res []Struct
+	for _, d := range f.Decls {
+		gd, ok := d.(*ast.GenDecl)
+		if !ok || gd.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gd.Specs {
+			ts := spec.(*ast.TypeSpec)
+			st, ok := ts.Type.(*ast.StructType)
+			if !ok {
+				continue
+			}
+			res = append(res, Struct{Name: ts.Name.Name, Fields: fieldsOf(st, gonfig)})
+		}
+	}
+	return res
+}
+
+// gonfigType returns name of type declared in package imported
+// as gonfig. Type parameters are omitted.
+func gonfigType(expr ast.Expr, gonfig string) string {
+	switch x := expr.(type) {
+	case *ast.IndexExpr:
+		expr = x.X
+	case *ast.IndexListExpr:
+		expr = x.X
+	}
+
+	sel, ok := expr.(*ast.SelectorExpr)
+	if !ok || gonfig == "" {
+		return ""
+	}
+	if x, ok := sel.X.(*ast.Ident); ok && x.Name == gonfig {
+		return sel.Sel.Name
+	}
+	return ""
+}
+
+// embeddedName returns name of embedded field declared by type expr.
+// Pointer, package qualifier and type arguments are omitted.
+func embeddedName(expr ast.Expr) string {
+	if x, ok := expr.(*ast.StarExpr); ok {
+		expr = x.X
+	}
+	switch x := expr.(type) {
+	case *ast.IndexExpr:
+		expr = x.X
+	case *ast.IndexListExpr:
+		expr = x.X
+	}
+	switch x := expr.(type) {
+	case *ast.SelectorExpr:
+		return x.Sel.Name
+	case *ast.Ident:
+		return x.Name
+	}
+	return types.ExprString(expr)
+}
+
+func fieldsOf(st *ast.StructType, gonfig string) []Field {
+	var res []Field
+	for _, af := range st.Fields.List {
+		f := Field{Type: types.ExprString(af.Type), Gonfig: gonfigType(af.Type, gonfig)}
+		if af.Tag != nil {
+			tag, _ := strconv.Unquote(af.Tag.Value)
+			f.Tag = reflect.StructTag(tag)
+		}
+
+		if len(af.Names) == 0 {
+			f.Embedded = true
+			f.Name = embeddedName(af.Type)
+			res = append(res, f)
+			continue
+		}
+		for _, n := range af.Names {
+			f.Name = n.Name
+			res = append(res, f)
+		}
+	}
+	return res
+}
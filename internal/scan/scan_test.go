@@ -0,0 +1,64 @@
+package scan_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/axkit/gonfig/internal/scan"
+)
+
+func TestDir(t *testing.T) {
+
+	dir := t.TempDir()
+	src := `package app
+
+import "github.com/axkit/gonfig"
+
+type Listener struct {
+	Port gonfig.Int ` + "`cfg:\"port\"`" + `
+	Host, Alias string
+}
+
+type Config struct {
+	Listener
+	Admin Listener
+}
+
+type ID int
+`
+	if err := os.WriteFile(filepath.Join(dir, "a.go"), []byte(src), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "a_test.go"), []byte("package app\n\ntype T struct{}\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	pkg, err := scan.Dir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if pkg.Name != "app" || len(pkg.Structs) != 2 {
+		t.Fatalf("unexpected package %+v", pkg)
+	}
+
+	l, ok := pkg.Struct("Listener")
+	if !ok || len(l.Fields) != 3 {
+		t.Fatalf("unexpected struct %+v", l)
+	}
+	if f := l.Fields[0]; f.Name != "Port" || f.Gonfig != "Int" || f.Type != "gonfig.Int" || f.Tag.Get("cfg") != "port" {
+		t.Errorf("unexpected field %+v", f)
+	}
+	if f := l.Fields[2]; f.Name != "Alias" || f.Type != "string" || f.Gonfig != "" {
+		t.Errorf("unexpected field %+v", f)
+	}
+
+	c, _ := pkg.Struct("Config")
+	if f := c.Fields[0]; !f.Embedded || f.Name != "Listener" {
+		t.Errorf("unexpected field %+v", f)
+	}
+	if f := c.Fields[1]; f.Embedded || f.Name != "Admin" || f.Type != "Listener" {
+		t.Errorf("unexpected field %+v", f)
+	}
+}
@@ -0,0 +1,291 @@
+package gonfig
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"unsafe"
+)
+
+var (
+	// ErrCycle raises when params reference each other.
+	ErrCycle = errors.New("reference cycle")
+
+	// ErrUnknownRef raises when value references param or
+	// environment variable which doesn't exist.
+	ErrUnknownRef = errors.New("unknown reference")
+)
+
+// expander resolves references ${code} and ${env:NAME} in values of
+// AString and ASecret params if interpolation is turned on by
+// WithInterpolation. Expanded value is re-evaluated when
+// a referenced param changes.
+type expander struct {
+	c    *Config
+	code string
+	v    Valuer
+
+	mux    sync.Mutex
+	tmpl   string
+	refs   []string
+	cancel []func()
+}
+
+// expanderOf returns expander of the shared memory or nil.
+// c can be nil.
+func (c *Config) expanderOf(mem unsafe.Pointer) *expander {
+	if c == nil || atomic.LoadInt32(&c.expanded) == 0 {
+		return nil
+	}
+	e, ok := c.expanders.Load(mem)
+	if !ok {
+		return nil
+	}
+	return e.(*expander)
+}
+
+// WithInterpolation turns on resolving of references ${code} and
+// ${env:NAME} in values of AString and ASecret params. Referenced
+// params and variables must exist when value is parsed. Without it
+// values are kept as is.
+func (c *Config) WithInterpolation() *Config {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	if c.interp {
+		return c
+	}
+	c.interp = true
+	for i := range c.list {
+		if ak := c.list[i].av.Kind(); ak == AString || ak == ASecret {
+			c.addExpander(c.list[i].code, c.list[i].av)
+		}
+	}
+	return c
+}
+
+// interpolated returns true if interpolation is turned on.
+func (c *Config) interpolated() bool {
+	c.mux.RLock()
+	defer c.mux.RUnlock()
+	return c.interp
+}
+
+// addExpander attaches expander to param created in container.
+// Must be called with locked c.mux.
+func (c *Config) addExpander(code string, v Valuer) {
+	e := &expander{c: c, code: code, v: v}
+	if _, loaded := c.expanders.LoadOrStore(v.(watchable).mem(), e); !loaded {
+		atomic.AddInt32(&c.expanded, 1)
+	}
+}
+
+// lookup returns param without locking container.
+func (c *Config) lookup(code string) (Valuer, bool) {
+	v, ok := c.byCode.Load(code)
+	if !ok {
+		return nil, false
+	}
+	return v.(Valuer), true
+}
+
+// refsOf returns codes referenced by template s.
+func refsOf(s string) []string {
+	var res []string
+	scan(s, func(ref string) string {
+		if !strings.HasPrefix(ref, "env:") {
+			res = append(res, ref)
+		}
+		return ""
+	})
+	return res
+}
+
+// scan replaces ${ref} by f(ref). $${ is replaced by ${.
+func scan(s string, f func(ref string) string) string {
+	if !strings.Contains(s, "${") {
+		return s
+	}
+
+	var b strings.Builder
+	for {
+		i := strings.Index(s, "${")
+		if i < 0 {
+			b.WriteString(s)
+			break
+		}
+
+		if i > 0 && s[i-1] == '$' {
+			b.WriteString(s[:i-1])
+			b.WriteString("${")
+			s = s[i+2:]
+			continue
+		}
+
+		j := strings.IndexByte(s[i:], '}')
+		if j < 0 {
+			b.WriteString(s)
+			break
+		}
+
+		b.WriteString(s[:i])
+		b.WriteString(f(strings.TrimSpace(s[i+2 : i+j])))
+		s = s[i+j+1:]
+	}
+	return b.String()
+}
+
+// expand resolves template s using current values of params.
+// Returns error if references make a cycle or plain string
+// references a secret.
+func (e *expander) expand(s string) (string, error) {
+	return e.expandWith(s, e.c.lookup)
+}
+
+// expandWith resolves template s using values returned by lookup.
+func (e *expander) expandWith(s string, lookup func(code string) (Valuer, bool)) (string, error) {
+	refs := refsOf(s)
+	if err := e.checkCycle(refs, map[string]bool{}); err != nil {
+		return "", err
+	}
+
+	for _, ref := range refs {
+		if v, ok := lookup(ref); ok && v.Kind() == ASecret && e.v.Kind() != ASecret {
+			return "", fmt.Errorf("param '%s' references secret '%s'", e.code, ref)
+		}
+	}
+	res, err := resolve(s, lookup)
+	if err != nil {
+		return "", fmt.Errorf("param '%s': %w", e.code, err)
+	}
+	return res, nil
+}
+
+func (e *expander) checkCycle(refs []string, visited map[string]bool) error {
+	for _, ref := range refs {
+		if ref == e.code {
+			return fmt.Errorf("%w: param '%s'", ErrCycle, e.code)
+		}
+		if visited[ref] {
+			continue
+		}
+		visited[ref] = true
+
+		v, ok := e.c.lookup(ref)
+		if !ok {
+			continue
+		}
+		re := e.c.expanderOf(v.(watchable).mem())
+		if re == nil {
+			continue
+		}
+
+		re.mux.Lock()
+		rrefs := re.refs
+		re.mux.Unlock()
+		if err := e.checkCycle(rrefs, visited); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resolve replaces references by values returned by lookup.
+// Returns error wrapping ErrUnknownRef if referenced param or
+// environment variable doesn't exist.
+func resolve(s string, lookup func(code string) (Valuer, bool)) (string, error) {
+	var err error
+	res := scan(s, func(ref string) string {
+		if name := strings.TrimPrefix(ref, "env:"); name != ref {
+			v, ok := os.LookupEnv(name)
+			if !ok && err == nil {
+				err = fmt.Errorf("%w: environment variable '%s'", ErrUnknownRef, name)
+			}
+			return v
+		}
+		if v, ok := lookup(ref); ok {
+			return Plain(v)
+		}
+		if err == nil {
+			err = fmt.Errorf("%w: param '%s'", ErrUnknownRef, ref)
+		}
+		return ""
+	})
+	return res, err
+}
+
+// commit remembers template s and subscribes to changes of
+// referenced params.
+func (e *expander) commit(s string) {
+	refs := refsOf(s)
+
+	e.mux.Lock()
+	defer e.mux.Unlock()
+
+	for _, cancel := range e.cancel {
+		cancel()
+	}
+	e.cancel = nil
+	e.tmpl = s
+	e.refs = refs
+
+	for _, ref := range refs {
+		if v, ok := e.c.lookup(ref); ok {
+			e.cancel = append(e.cancel, e.c.subscribe(ref, v.(watchable).mem(), e.refresh))
+		}
+	}
+}
+
+// refresh re-evaluates template after referenced param changed.
+// Value violating validation rules is not assigned.
+func (e *expander) refresh(old, new string) {
+	e.mux.Lock()
+	tmpl := e.tmpl
+	e.mux.Unlock()
+
+	s, err := resolve(tmpl, e.c.lookup)
+	if err != nil {
+		return
+	}
+	mem := e.v.(watchable).mem()
+
+	switch x := e.v.(type) {
+	case *String:
+		n := NewString()
+		n.Set(s)
+		if e.c.validate(mem, n) == nil {
+			x.Set(s)
+		}
+	case *Secret:
+		n := NewSecret()
+		n.Set(s)
+		if e.c.validate(mem, n) == nil {
+			x.Set(s)
+		}
+	}
+}
+
+// templateOf returns string which parsing reproduces value of
+// AString or ASecret param v, including its references.
+func (c *Config) templateOf(v Valuer) string {
+	s := Plain(v)
+	e := c.expanderOf(v.(watchable).mem())
+	if e == nil {
+		return s
+	}
+
+	e.mux.Lock()
+	tmpl := e.tmpl
+	e.mux.Unlock()
+	if r, err := resolve(tmpl, e.c.lookup); err == nil && tmpl != s && r == s {
+		return tmpl
+	}
+	return escape(s)
+}
+
+// escape escapes references in s.
+func escape(s string) string {
+	return strings.ReplaceAll(s, "${", "$${")
+}
@@ -0,0 +1,177 @@
+package gonfig_test
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/axkit/gonfig"
+)
+
+func TestConfig_Interpolation(t *testing.T) {
+
+	type store struct {
+		Host      gonfig.String `cfg:"host" default:"localhost"`
+		Port      gonfig.Int    `cfg:"port" default:"8080"`
+		APIURL    gonfig.String `cfg:"api_url" default:"http://${host}:${port}/api"`
+		ReportURL gonfig.String `cfg:"report_url" default:"${api_url}/report"`
+		Literal   gonfig.String `cfg:"literal" default:"$${host}"`
+	}
+
+	os.Setenv("INTERPOLATION_TEST_USER", "admin")
+	defer os.Unsetenv("INTERPOLATION_TEST_USER")
+
+	var s store
+	cfg := gonfig.New().WithInterpolation()
+	if errs := cfg.BindStruct(&s); len(errs) != 0 {
+		t.Fatal(errs)
+	}
+
+	if s.APIURL.Val() != "http://localhost:8080/api" {
+		t.Errorf("unexpected api_url %s", s.APIURL.Val())
+	}
+
+	if s.ReportURL.Val() != "http://localhost:8080/api/report" {
+		t.Errorf("unexpected report_url %s", s.ReportURL.Val())
+	}
+
+	if s.Literal.Val() != "${host}" {
+		t.Errorf("unexpected literal %s", s.Literal.Val())
+	}
+
+	s.Host.Set("example.com")
+	s.Port.Set(443)
+	if s.ReportURL.Val() != "http://example.com:443/api/report" {
+		t.Errorf("dependent value is not re-evaluated: %s", s.ReportURL.Val())
+	}
+
+	p, _ := cfg.Get("api_url")
+	if err := p.Parse("https://${env:INTERPOLATION_TEST_USER}@${host}"); err != nil {
+		t.Error(err)
+	}
+	if s.ReportURL.Val() != "https://admin@example.com/report" {
+		t.Errorf("unexpected report_url %s", s.ReportURL.Val())
+	}
+
+	// api_url doesn't reference port anymore.
+	s.Port.Set(80)
+	if s.APIURL.Val() != "https://admin@example.com" {
+		t.Errorf("unexpected api_url %s", s.APIURL.Val())
+	}
+
+	h, _ := cfg.Get("host")
+	if err := h.Parse("${report_url}"); !errors.Is(err, gonfig.ErrCycle) {
+		t.Errorf("expected ErrCycle, got %v", err)
+	}
+	if s.Host.Val() != "example.com" {
+		t.Error("value must be kept")
+	}
+
+	err := cfg.Update(func(tx gonfig.Tx) error {
+		if err := tx.Parse("host", "db.local"); err != nil {
+			return err
+		}
+		return tx.Parse("api_url", "tcp://${host}")
+	})
+	if err != nil {
+		t.Error(err)
+	}
+	if s.ReportURL.Val() != "tcp://db.local/report" {
+		t.Errorf("unexpected report_url %s", s.ReportURL.Val())
+	}
+}
+
+func TestConfig_InterpolationUnknownReference(t *testing.T) {
+
+	cfg := gonfig.New().WithInterpolation()
+	dsn := cfg.MustParam("dsn", gonfig.AString)
+	if err := dsn.Parse("postgres://${db_host}/app"); !errors.Is(err, gonfig.ErrUnknownRef) {
+		t.Errorf("expected ErrUnknownRef, got %v", err)
+	}
+	if err := dsn.Parse("postgres://${env:INTERPOLATION_TEST_UNSET}/app"); !errors.Is(err, gonfig.ErrUnknownRef) {
+		t.Errorf("expected ErrUnknownRef, got %v", err)
+	}
+	if dsn.(*gonfig.String).Val() != "" {
+		t.Errorf("value must be kept, got %s", dsn.(*gonfig.String).Val())
+	}
+
+	cfg.MustParam("db_host", gonfig.AString).Parse("db1")
+	if err := dsn.Parse("postgres://${db_host}/app"); err != nil {
+		t.Fatal(err)
+	}
+	if dsn.(*gonfig.String).Val() != "postgres://db1/app" {
+		t.Errorf("unexpected dsn %s", dsn.(*gonfig.String).Val())
+	}
+}
+
+func TestMapSource_Interpolation(t *testing.T) {
+
+	cfg := gonfig.New().WithInterpolation()
+	err := gonfig.MapSource{
+		"api":  "http://${host}:${port}",
+		"host": "localhost",
+		"port": "${env:INTERPOLATION_TEST_UNSET}",
+	}.ApplyTo(cfg, true)
+	if !errors.Is(err, gonfig.ErrUnknownRef) {
+		t.Errorf("expected ErrUnknownRef, got %v", err)
+	}
+
+	err = gonfig.MapSource{"api": "http://${host}:${port}", "host": "localhost", "port": "80"}.ApplyTo(cfg, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := cfg.Get("api"); gonfig.Plain(v) != "http://localhost:80" {
+		t.Errorf("unexpected api %s", gonfig.Plain(v))
+	}
+}
+
+func TestConfig_InterpolationOff(t *testing.T) {
+
+	cfg := gonfig.New()
+	cfg.MustParam("ss", gonfig.AString).Parse("x")
+
+	p := cfg.MustParam("password", gonfig.AString)
+	if err := p.Parse("pa${ss}word"); err != nil {
+		t.Fatal(err)
+	}
+	if p.(*gonfig.String).Val() != "pa${ss}word" {
+		t.Errorf("value must be kept as is, got %s", p.(*gonfig.String).Val())
+	}
+
+	s := gonfig.NewString()
+	s.Parse("pa${ss}word")
+	if s.Val() != p.(*gonfig.String).Val() {
+		t.Errorf("unbound value %s differs from param value", s.Val())
+	}
+
+	// snapshot restores literal value.
+	snap := cfg.Snapshot("password")
+	p.Parse("other")
+	if err := cfg.Restore(snap); err != nil || p.(*gonfig.String).Val() != "pa${ss}word" {
+		t.Errorf("unexpected restored value %s %v", p.(*gonfig.String).Val(), err)
+	}
+
+	// params existing before interpolation is turned on.
+	cfg.WithInterpolation()
+	if err := p.Parse("pa${ss}word"); err != nil || p.(*gonfig.String).Val() != "paxword" {
+		t.Errorf("unexpected value %s %v", p.(*gonfig.String).Val(), err)
+	}
+}
+
+func TestConfig_InterpolationSecret(t *testing.T) {
+
+	cfg := gonfig.New().WithInterpolation()
+	cfg.MustParam("password", gonfig.ASecret).Parse("qwerty")
+
+	if err := cfg.MustParam("url", gonfig.AString).Parse("http://u:${password}@h"); err == nil {
+		t.Error("string must not reference secret")
+	}
+
+	dsn := cfg.MustParam("dsn", gonfig.ASecret)
+	if err := dsn.Parse("u:${password}@h"); err != nil {
+		t.Error(err)
+	}
+	if dsn.(*gonfig.Secret).Reveal() != "u:qwerty@h" {
+		t.Errorf("unexpected dsn %s", dsn.(*gonfig.Secret).Reveal())
+	}
+}
@@ -0,0 +1,221 @@
+package gonfig
+
+import (
+	"encoding/json"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"unsafe"
+)
+
+// NonBindedIntSlice is returned by method Val
+// if IntSlice is not initialized yet.
+var NonBindedIntSlice []int
+
+// IntSlice implements atomic slice of ints.
+type IntSlice struct {
+	ref *sliceRef
+
+	// c is container the param belongs to.
+	c *Config
+}
+
+// NewIntSlice returns atomic slice of ints.
+func NewIntSlice() *IntSlice {
+	return &IntSlice{ref: new(sliceRef)}
+}
+
+// Kind returns AIntSlice.
+func (a *IntSlice) Kind() AKind {
+	return AIntSlice
+}
+
+func (a *IntSlice) load() *sliceRef {
+	return (*sliceRef)(atomic.LoadPointer((*unsafe.Pointer)(unsafe.Pointer(&a.ref))))
+}
+
+// Set assigns a copy of is atomically. Initializes if was not before.
+func (a *IntSlice) Set(is []int) {
+	if a.owner().rejected(a.mem(), func() Valuer {
+		n := NewIntSlice()
+		n.Set(is)
+		return n
+	}) {
+		return
+	}
+	a.set(is, nil)
+}
+
+// set is like Set, the change is attributed to by.
+func (a *IntSlice) set(is []int, by *author) {
+	cp := make([]int, len(is))
+	copy(cp, is)
+
+	ref := a.load()
+	if n := a.owner().notifierOf(unsafe.Pointer(ref)); n != nil {
+		n.change(by, func() (string, string, bool) {
+			old, _ := ref.val.Swap(cp).([]int)
+			return jsonInts(old), jsonInts(cp), !slices.Equal(old, cp)
+		})
+		return
+	}
+	if ref == nil {
+		n := NewIntSlice()
+		a.Bind(n)
+		ref = n.ref
+	}
+	ref.val.Store(cp)
+}
+
+// Val returns a copy of value. Returns NonBindedIntSlice
+// if it's not binded to params container.
+func (a *IntSlice) Val() []int {
+	ref := a.load()
+	if ref == nil {
+		return NonBindedIntSlice
+	}
+	is, _ := ref.val.Load().([]int)
+	res := make([]int, len(is))
+	copy(res, is)
+	return res
+}
+
+// Len returns number of items without copying the slice.
+func (a *IntSlice) Len() int {
+	ref := a.load()
+	if ref == nil {
+		return 0
+	}
+	is, _ := ref.val.Load().([]int)
+	return len(is)
+}
+
+// SetSeparator sets separator used by Parse and String.
+// Separator is shared by all binded variables.
+func (a *IntSlice) SetSeparator(sep string) {
+	ref := a.load()
+	if ref == nil {
+		n := NewIntSlice()
+		a.Bind(n)
+		ref = n.ref
+	}
+	ref.sep.Store(sep)
+}
+
+// IsBinded returns true if IntSlice bineded to params container.
+func (a *IntSlice) IsBinded() bool {
+	return a.load() != nil
+}
+
+func (a *IntSlice) mem() unsafe.Pointer {
+	return unsafe.Pointer(a.load())
+}
+
+func (a *IntSlice) owner() *Config {
+	return loadOwner(&a.c)
+}
+
+func (a *IntSlice) own(c *Config) {
+	storeOwner(&a.c, c)
+}
+
+func (a *IntSlice) assign(v Valuer, by *author) {
+	a.set(v.(*IntSlice).Val(), by)
+}
+
+// Bind binds current atomic variable to variable identified by to.
+// As a result two IntSlice address to the same variable.
+func (a *IntSlice) Bind(to *IntSlice) {
+	ptr := atomic.LoadPointer((*unsafe.Pointer)(unsafe.Pointer(&to.ref)))
+	if ptr != nil {
+		storeOwner(&a.c, to.owner())
+		atomic.StorePointer((*unsafe.Pointer)(unsafe.Pointer(&a.ref)), ptr)
+	}
+}
+
+// String implements Stringer interface. Items are joined by separator.
+func (a *IntSlice) String() string {
+	ref := a.load()
+	if ref == nil {
+		return ""
+	}
+	is, _ := ref.val.Load().([]int)
+	return joinInts(is, ref.separator())
+}
+
+func joinInts(is []int, sep string) string {
+	ss := make([]string, len(is))
+	for i := range is {
+		ss[i] = strconv.Itoa(is[i])
+	}
+	return strings.Join(ss, sep)
+}
+
+// jsonInts returns is as JSON array.
+func jsonInts(is []int) string {
+	if is == nil {
+		is = []int{}
+	}
+	buf, _ := json.Marshal(is)
+	return string(buf)
+}
+
+// Parse converts input argument and assigns to value. Accepts
+// items delimited by separator or JSON array of numbers.
+// Value is not changed if any item is not a number or
+// value violates validation rules.
+func (a *IntSlice) Parse(s string) error {
+	s = strings.TrimSpace(s)
+
+	var is []int
+	if isJSONArray(s) {
+		if err := json.Unmarshal([]byte(s), &is); err != nil {
+			return err
+		}
+	} else {
+		sep := DefaultSliceSeparator
+		if ref := a.load(); ref != nil {
+			sep = ref.separator()
+		}
+
+		items := splitSlice(s, sep)
+		is = make([]int, len(items))
+		for i := range items {
+			v, err := strconv.Atoi(items[i])
+			if err != nil {
+				return err
+			}
+			is[i] = v
+		}
+	}
+
+	if vr := a.owner().validatorOf(a.mem()); vr != nil {
+		n := NewIntSlice()
+		n.Set(is)
+		if err := vr.check(n); err != nil {
+			return err
+		}
+	}
+	a.Set(is)
+	return nil
+}
+
+// MarshalJSON implement Marshaller interface.
+func (a IntSlice) MarshalJSON() ([]byte, error) {
+	is := a.Val()
+	if is == nil {
+		is = []int{}
+	}
+	return json.Marshal(is)
+}
+
+// UnmarshalJSON implement Unmarshaller interface.
+func (a *IntSlice) UnmarshalJSON(buf []byte) error {
+	var is []int
+	if err := json.Unmarshal(buf, &is); err != nil {
+		return err
+	}
+	a.Set(is)
+	return nil
+}
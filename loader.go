@@ -0,0 +1,353 @@
+package gonfig
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"sort"
+)
+
+// Origin describes where effective value of parameter came from.
+type Origin struct {
+	// Source is a name of source given to Loader.Add.
+	Source string
+
+	// Location is a place inside the source: file path,
+	// environment variable, command line flag, etc.
+	Location string
+}
+
+// String implements Stringer interface.
+func (o Origin) String() string {
+	if o.Location == "" {
+		return o.Source
+	}
+	return o.Source + ":" + o.Location
+}
+
+// RecordOrigin sets origin o of param identified by code if g
+// implements Originer. Sources call it for values assigned to
+// existing params, so Audit doesn't report them as defaulted.
+// Container given to sources by Loader is not Originer, Loader
+// records origins itself.
+func RecordOrigin(g Configer, code string, o Origin) {
+	if og, ok := g.(Originer); ok {
+		og.SetOrigin(code, o)
+	}
+}
+
+// A Locator is an interface wrapping a single method Location.
+//
+// Location returns a place inside the source where value of the param
+// identified by code was taken from. Sources implement Locator
+// optionally.
+type Locator interface {
+	Location(code string) string
+}
+
+type namedSource struct {
+	name string
+	src  ConfigSourcer
+}
+
+// Loader applies sources in the order they were added. Values from
+// later sources overwrite values from earlier ones, so sources
+// are expected to be added in precedence order, e.g.
+// defaults, file, env, flags.
+//
+// Loader records origin of every parameter written by the source.
+type Loader struct {
+	sources []namedSource
+	logf    func(format string, args ...interface{})
+	dec     Decrypter
+}
+
+// NewLoader returns empty Loader. Reloads are logged by log.Printf.
+func NewLoader() *Loader {
+	return &Loader{logf: log.Printf}
+}
+
+// WithLogf sets function logging reloads. nil disables logging.
+func (l *Loader) WithLogf(f func(format string, args ...interface{})) *Loader {
+	l.logf = f
+	return l
+}
+
+// WithDecrypter makes all sources decrypt values in form enc:<base64>.
+func (l *Loader) WithDecrypter(d Decrypter) *Loader {
+	l.dec = d
+	return l
+}
+
+// Add appends source identified by name to the end of the list.
+func (l *Loader) Add(name string, src ConfigSourcer) *Loader {
+	l.sources = append(l.sources, namedSource{name: name, src: src})
+	return l
+}
+
+// Load applies all sources to config container with overwrite and
+// records origin of every parameter written by the source if g
+// implements Originer.
+// Stops on first error.
+func (l *Loader) Load(g Configer) error {
+	for _, ns := range l.sources {
+		if err := l.apply(g, ns); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Reload applies all sources again in the same order and returns
+// changes of param values. Stops on first error or if ctx is done,
+// changes applied before are returned. Changes are returned only
+// if g implements Snapshotter.
+func (l *Loader) Reload(ctx context.Context, g Configer) ([]Change, error) {
+	sg, _ := g.(Snapshotter)
+	var before *Snapshot
+	if sg != nil {
+		before = sg.Snapshot()
+	}
+
+	var err error
+	for _, ns := range l.sources {
+		if err = ctx.Err(); err != nil {
+			break
+		}
+		if err = l.apply(g, ns); err != nil {
+			break
+		}
+	}
+
+	var changes []Change
+	if sg != nil {
+		changes = diff(before, sg.Snapshot())
+	}
+	if l.logf != nil {
+		for _, c := range changes {
+			l.logf("gonfig: param '%s' changed from '%s' to '%s'", c.Code, c.Old, c.New)
+		}
+		if err != nil {
+			l.logf("gonfig: reload failed: %v", err)
+		}
+	}
+	return changes, err
+}
+
+// ReloadOnSignal calls Reload every time the process receives one of
+// signals sigs, syscall.SIGHUP if sigs are not given, until ctx is done.
+// Returns immediately. On platforms without SIGHUP, such as js, wasip1
+// and plan9, signals must be given, otherwise nothing is reloaded.
+func (l *Loader) ReloadOnSignal(ctx context.Context, g Configer, sigs ...os.Signal) {
+	if len(sigs) == 0 {
+		sigs = defaultReloadSignals
+	}
+	if len(sigs) == 0 {
+		if l.logf != nil {
+			l.logf("gonfig: no signals to reload on")
+		}
+		return
+	}
+
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, sigs...)
+
+	go func() {
+		defer signal.Stop(ch)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case sig := <-ch:
+				if l.logf != nil {
+					l.logf("gonfig: %s received, reloading", sig)
+				}
+				l.Reload(ctx, g)
+			}
+		}
+	}()
+}
+
+// diff returns changes between snapshots. New params are
+// reported with empty Old. Secrets are masked.
+func diff(before, after *Snapshot) []Change {
+	var res []Change
+	for _, code := range after.Codes() {
+		nv, _ := after.Get(code)
+		ov, ok := before.Get(code)
+		if ok && Plain(ov) == Plain(nv) {
+			continue
+		}
+
+		c := Change{Code: code, New: fmt.Sprint(nv)}
+		if ok {
+			c.Old = fmt.Sprint(ov)
+		}
+		res = append(res, c)
+	}
+	return res
+}
+
+// apply applies single source recording origins.
+func (l *Loader) apply(g Configer, ns namedSource) error {
+	name, src := ns.name, ns.src
+	if l.dec != nil {
+		src = WithDecrypter(src, l.dec)
+	}
+
+	t := &tracker{
+		Configer: g,
+		codes:    make(map[string]struct{}),
+		ctx:      WithSource(context.Background(), name),
+		logf:     l.logf,
+	}
+	err := src.ApplyTo(t, true)
+
+	codes := make([]string, 0, len(t.codes))
+	for code := range t.codes {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+
+	og, _ := g.(Originer)
+	loc, _ := src.(Locator)
+	for _, code := range codes {
+		if og == nil {
+			break
+		}
+		o := Origin{Source: name}
+		if loc != nil {
+			o.Location = loc.Location(code)
+		}
+		og.SetOrigin(code, o)
+	}
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	return nil
+}
+
+// tracker records codes of params assigned by the source. Values
+// are recorded when they are assigned by Parse of valuers returned
+// by Param, MustParam or Get. Changes are attributed to the source
+// carried by ctx.
+type tracker struct {
+	Configer
+	codes map[string]struct{}
+	ctx   context.Context
+	logf  func(format string, args ...interface{})
+}
+
+func (t *tracker) Param(code string, ak AKind) (Valuer, error) {
+	v, err := t.Configer.Param(code, ak)
+	if err != nil {
+		return nil, err
+	}
+	return &trackedValuer{Valuer: v, t: t, code: code}, nil
+}
+
+func (t *tracker) MustParam(code string, ak AKind) Valuer {
+	return &trackedValuer{Valuer: t.Configer.MustParam(code, ak), t: t, code: code}
+}
+
+func (t *tracker) Get(code string) (Valuer, bool) {
+	v, ok := t.Configer.Get(code)
+	if !ok {
+		return nil, false
+	}
+	return &trackedValuer{Valuer: v, t: t, code: code}, true
+}
+
+// trackedValuer parses values via ParseContext if container
+// implements Updater.
+type trackedValuer struct {
+	Valuer
+	t    *tracker
+	code string
+}
+
+// Parse parses value and records the code if value is assigned.
+// Changes of static params rejected after Freeze are not errors,
+// they are pending till restart.
+func (v *trackedValuer) Parse(s string) error {
+	var err error
+	if u, ok := v.t.Configer.(Updater); ok {
+		err = u.ParseContext(v.t.ctx, v.code, s)
+	} else {
+		err = v.Valuer.Parse(s)
+	}
+	switch {
+	case errors.Is(err, ErrStatic):
+		// origin is kept as value is not applied.
+		if v.t.logf != nil {
+			v.t.logf("gonfig: %v", err)
+		}
+		return nil
+	case err != nil:
+		return err
+	}
+	v.t.codes[v.code] = struct{}{}
+	return nil
+}
+
+// MapSource implements ConfigSourcer reading parameters from the map.
+// It's handy for hardcoded defaults. New params are created as AString.
+type MapSource map[string]string
+
+// ApplyTo applies map to config container. Existing parameters
+// are overwritten if ow is true. Values referencing params of the
+// map are applied after them.
+func (m MapSource) ApplyTo(g Configer, ow bool) error {
+	codes := make([]string, 0, len(m))
+	for code := range m {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+
+	return ApplyOrdered(codes, func(code string) error {
+		ak := AString
+		if p, ok := g.Get(code); ok {
+			if !ow {
+				return nil
+			}
+			ak = p.Kind()
+		}
+
+		p, err := g.Param(code, ak)
+		if err != nil {
+			return err
+		}
+		if err := p.Parse(m[code]); err != nil {
+			return fmt.Errorf("param '%s': %w", code, err)
+		}
+		return nil
+	})
+}
+
+// ApplyOrdered calls f for every code. Codes failed with ErrUnknownRef
+// are retried after the others while any of them succeeds, so
+// values may reference params applied later by the same source.
+func ApplyOrdered(codes []string, f func(code string) error) error {
+	for len(codes) > 0 {
+		var pending []string
+		var last error
+		for _, code := range codes {
+			err := f(code)
+			switch {
+			case errors.Is(err, ErrUnknownRef):
+				pending, last = append(pending, code), err
+			case err != nil:
+				return err
+			}
+		}
+		if len(pending) == len(codes) {
+			return last
+		}
+		codes = pending
+	}
+	return nil
+}
@@ -0,0 +1,154 @@
+package gonfig_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/axkit/gonfig"
+	"github.com/axkit/gonfig/gonfigenv"
+	"github.com/axkit/gonfig/gonfigfile"
+	"github.com/axkit/gonfig/gonfigflag"
+)
+
+func TestLoader_Load(t *testing.T) {
+
+	path := filepath.Join(t.TempDir(), "app.yaml")
+	if err := os.WriteFile(path, []byte("port: 8080\nlisten: 0.0.0.0\nname: file\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	os.Setenv("LOADERTEST_LISTEN", "127.0.0.1")
+	os.Setenv("LOADERTEST_NAME", "env")
+	defer os.Unsetenv("LOADERTEST_LISTEN")
+	defer os.Unsetenv("LOADERTEST_NAME")
+
+	l := gonfig.NewLoader().
+		Add("defaults", gonfig.MapSource{"port": "80", "debug": "false", "name": "default"}).
+		Add("file", gonfigfile.NewFileSource(path, "")).
+		Add("env", gonfigenv.NewEnvSource("LOADERTEST_", true)).
+		Add("flags", gonfigflag.NewFlagSource([]string{"--name=flag"}))
+
+	cfg := gonfig.New()
+	if err := l.Load(cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]struct {
+		val    string
+		origin string
+	}{
+		"debug":  {"false", "defaults"},
+		"port":   {"8080", "file:" + path},
+		"listen": {"127.0.0.1", "env:LOADERTEST_LISTEN"},
+		"name":   {"flag", "flags:--name=flag"},
+	}
+
+	for code, e := range expected {
+		v, ok := cfg.Get(code)
+		if !ok {
+			t.Errorf("param '%s' not found", code)
+			continue
+		}
+		if s := v.(interface{ String() string }).String(); s != e.val {
+			t.Errorf("param '%s': expected %s, got %s", code, e.val, s)
+		}
+		o, ok := cfg.Origin(code)
+		if !ok {
+			t.Errorf("param '%s': origin not found", code)
+			continue
+		}
+		if o.String() != e.origin {
+			t.Errorf("param '%s': expected origin %s, got %s", code, e.origin, o)
+		}
+	}
+
+	cfg.MustParam("bound_only", gonfig.AInt)
+	if _, ok := cfg.Origin("bound_only"); ok {
+		t.Error("param not provided by any source must have no origin")
+	}
+
+	cfg.MustParam("limit", gonfig.AInt)
+	err := gonfig.NewLoader().
+		Add("bad", gonfig.MapSource{"limit": "abc"}).
+		Load(cfg)
+	if err == nil {
+		t.Error("error expected")
+	}
+	if _, ok := cfg.Origin("limit"); ok {
+		t.Error("value not assigned must have no origin")
+	}
+}
+
+// getSource assigns values of existing params via Get and Parse.
+type getSource map[string]string
+
+func (s getSource) ApplyTo(g gonfig.Configer, ow bool) error {
+	for code, val := range s {
+		if p, ok := g.Get(code); ok {
+			if err := p.Parse(val); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func TestLoader_OriginGet(t *testing.T) {
+
+	cfg := gonfig.New()
+	cfg.MustParam("port", gonfig.AInt)
+
+	if err := gonfig.NewLoader().Add("custom", getSource{"port": "80", "missing": "x"}).Load(cfg); err != nil {
+		t.Fatal(err)
+	}
+	if o, ok := cfg.Origin("port"); !ok || o.Source != "custom" {
+		t.Errorf("expected origin custom, got %v", o)
+	}
+	if cfg.IsExist("missing") {
+		t.Error("unexpected param")
+	}
+}
+
+func TestLoader_Reload(t *testing.T) {
+
+	path := filepath.Join(t.TempDir(), "app.yaml")
+	if err := os.WriteFile(path, []byte("port: 8080\nhost: a\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	var logged []string
+	l := gonfig.NewLoader().
+		Add("file", gonfigfile.NewFileSource(path, "")).
+		WithLogf(func(format string, args ...interface{}) {
+			logged = append(logged, fmt.Sprintf(format, args...))
+		})
+
+	cfg := gonfig.New()
+	if err := l.Load(cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	os.WriteFile(path, []byte("port: 9090\nhost: a\n"), 0600)
+
+	changes, err := l.Reload(context.Background(), cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(changes) != 1 || changes[0] != (gonfig.Change{Code: "port", Old: "8080", New: "9090"}) {
+		t.Errorf("unexpected changes: %v", changes)
+	}
+
+	if len(logged) != 1 || logged[0] != "gonfig: param 'port' changed from '8080' to '9090'" {
+		t.Errorf("unexpected log: %v", logged)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := l.Reload(ctx, cfg); err != context.Canceled {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
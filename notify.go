@@ -0,0 +1,311 @@
+package gonfig
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unsafe"
+)
+
+// ErrNotFound raises when param identified by code is not in container.
+var ErrNotFound = errors.New("param not found")
+
+// DefaultWatchBuffer is a capacity of channel returned by Watch.
+var DefaultWatchBuffer = 16
+
+// Change describes a change of param value. Values are strings
+// parsed back to the same value, slices are JSON arrays.
+type Change struct {
+	Code string
+	Old  string
+	New  string
+}
+
+// watchable is implemented by all valuers. mem returns address
+// of memory shared by binded valuers. owner returns container
+// the param belongs to, own sets it. Binded valuers share owner.
+type watchable interface {
+	mem() unsafe.Pointer
+	owner() *Config
+	own(c *Config)
+}
+
+func loadOwner(p **Config) *Config {
+	return (*Config)(atomic.LoadPointer((*unsafe.Pointer)(unsafe.Pointer(p))))
+}
+
+func storeOwner(p **Config, c *Config) {
+	atomic.StorePointer((*unsafe.Pointer)(unsafe.Pointer(p)), unsafe.Pointer(c))
+}
+
+type subscription struct {
+	f func(old, new string)
+
+	// plain subscriber receives values of secrets unmasked.
+	plain bool
+
+	// record is called instead of f if set.
+	record func(c Change, at time.Time, by *author)
+}
+
+// queued is a change waiting for delivery.
+type queued struct {
+	Change
+
+	// masked is true if values must be masked for subscribers.
+	masked bool
+
+	at time.Time
+	by *author
+}
+
+// notifier delivers changes of a single param to subscribers.
+type notifier struct {
+	code string
+
+	mux   sync.Mutex
+	subs  []*subscription
+	queue []queued
+	busy  bool
+}
+
+// notifierOf returns notifier of the shared memory or nil
+// if nobody is subscribed. c can be nil.
+func (c *Config) notifierOf(mem unsafe.Pointer) *notifier {
+	if c == nil || atomic.LoadInt32(&c.watched) == 0 {
+		return nil
+	}
+	n, ok := c.notifiers.Load(mem)
+	if !ok {
+		return nil
+	}
+	return n.(*notifier)
+}
+
+// change calls swap and queues the change if value was changed.
+// Swaps are serialized, so changes are delivered in the same order
+// values were written. The first writer delivers the queue to
+// subscribers, other writers return immediately.
+// The change is attributed to by, which can be nil.
+func (n *notifier) change(by *author, swap func() (old, new string, changed bool)) {
+	n.push(false, by, swap)
+}
+
+// changeSecret is like change but values are masked for subscribers
+// except plain ones.
+func (n *notifier) changeSecret(by *author, swap func() (old, new string, changed bool)) {
+	n.push(true, by, swap)
+}
+
+func (n *notifier) push(masked bool, by *author, swap func() (old, new string, changed bool)) {
+	n.mux.Lock()
+	old, new, changed := swap()
+	if !changed {
+		n.mux.Unlock()
+		return
+	}
+
+	n.queue = append(n.queue, queued{
+		Change: Change{Code: n.code, Old: old, New: new},
+		masked: masked,
+		at:     time.Now(),
+		by:     by,
+	})
+	if n.busy {
+		n.mux.Unlock()
+		return
+	}
+
+	n.busy = true
+	n.deliver()
+}
+
+// hold postpones delivery of changes until release.
+// Returns false if changes are being delivered by another goroutine.
+func (n *notifier) hold() bool {
+	n.mux.Lock()
+	defer n.mux.Unlock()
+	if n.busy {
+		return false
+	}
+	n.busy = true
+	return true
+}
+
+// release delivers changes queued since hold.
+func (n *notifier) release() {
+	n.mux.Lock()
+	n.deliver()
+}
+
+// hold postpones delivery of changes of param v until unlock,
+// so subscribers are not called with locked c.mux.
+// Must be called with locked c.mux.
+func (c *Config) hold(v Valuer) {
+	w, ok := v.(watchable)
+	if !ok {
+		return
+	}
+	if n := c.notifierOf(w.mem()); n != nil && n.hold() {
+		c.held = append(c.held, n)
+	}
+}
+
+// unlock unlocks c.mux and delivers changes held by hold.
+func (c *Config) unlock() {
+	held := c.held
+	c.held = nil
+	c.mux.Unlock()
+	for _, n := range held {
+		n.release()
+	}
+}
+
+// deliver delivers queued changes. Must be called with locked mux
+// by the goroutine which set busy. Unlocks mux.
+func (n *notifier) deliver() {
+	for len(n.queue) > 0 {
+		c := n.queue[0]
+		n.queue = n.queue[1:]
+		subs := n.subs
+		n.mux.Unlock()
+		for _, s := range subs {
+			ch := c.Change
+			if c.masked && !s.plain {
+				ch.Old, ch.New = mask(ch.Old), mask(ch.New)
+			}
+			if s.record != nil {
+				s.record(ch, c.at, c.by)
+				continue
+			}
+			s.f(ch.Old, ch.New)
+		}
+		n.mux.Lock()
+	}
+	n.busy = false
+	n.mux.Unlock()
+}
+
+func (c *Config) subscribe(code string, mem unsafe.Pointer, f func(old, new string)) func() {
+	return c.subscribeWith(code, mem, &subscription{f: f})
+}
+
+func (c *Config) subscribeWith(code string, mem unsafe.Pointer, s *subscription) func() {
+	for {
+		v, loaded := c.notifiers.LoadOrStore(mem, &notifier{code: code})
+		n := v.(*notifier)
+		if !loaded {
+			atomic.AddInt32(&c.watched, 1)
+		}
+
+		n.mux.Lock()
+		if cur, ok := c.notifiers.Load(mem); !ok || cur != n {
+			// notifier was removed by the last unsubscribe.
+			n.mux.Unlock()
+			continue
+		}
+		n.subs = append(n.subs[:len(n.subs):len(n.subs)], s)
+		n.mux.Unlock()
+
+		var once sync.Once
+		return func() {
+			once.Do(func() { c.unsubscribe(mem, n, s) })
+		}
+	}
+}
+
+func (c *Config) unsubscribe(mem unsafe.Pointer, n *notifier, s *subscription) {
+	n.mux.Lock()
+	defer n.mux.Unlock()
+
+	subs := make([]*subscription, 0, len(n.subs))
+	for _, x := range n.subs {
+		if x != s {
+			subs = append(subs, x)
+		}
+	}
+	n.subs = subs
+
+	if len(subs) == 0 {
+		c.notifiers.Delete(mem)
+		atomic.AddInt32(&c.watched, -1)
+	}
+}
+
+// OnChange subscribes f to changes of param identified by code.
+// Returns function cancelling the subscription.
+//
+// f is called after Parse or Set actually changed the value, no matter
+// which binded variable was used. f is called synchronously, outside
+// of container lock, by the goroutine which changed the value. If
+// several goroutines change the value concurrently, changes are
+// delivered one by one in the order they were written, by the goroutine
+// which started delivery first. Subscribers are called in order
+// of subscription. f may change the param, the change is delivered
+// after f returns.
+func (c *Config) OnChange(code string, f func(old, new string)) (func(), error) {
+	c.mux.RLock()
+	idx, ok := c.idx[code]
+	var v Valuer
+	if ok {
+		v = c.list[idx].av
+	}
+	c.mux.RUnlock()
+
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	w, ok := v.(watchable)
+	if !ok {
+		return nil, ErrUnknownKind
+	}
+	return c.subscribe(code, w.mem(), f), nil
+}
+
+// Watch returns channel receiving changes of param identified by code
+// and function cancelling the subscription and closing the channel.
+//
+// Changes are sent in order they were written. Channel capacity is
+// DefaultWatchBuffer, if receiver is too slow, the oldest changes
+// are dropped, so the last change is always delivered.
+func (c *Config) Watch(code string) (<-chan Change, func(), error) {
+	ch := make(chan Change, DefaultWatchBuffer)
+	var mux sync.Mutex
+	var closed bool
+
+	cancel, err := c.OnChange(code, func(old, new string) {
+		mux.Lock()
+		defer mux.Unlock()
+		if closed {
+			return
+		}
+		ev := Change{Code: code, Old: old, New: new}
+		for {
+			select {
+			case ch <- ev:
+				return
+			default:
+			}
+			// drop the oldest change.
+			select {
+			case <-ch:
+			default:
+			}
+		}
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return ch, func() {
+		cancel()
+		mux.Lock()
+		defer mux.Unlock()
+		if !closed {
+			closed = true
+			close(ch)
+		}
+	}, nil
+}
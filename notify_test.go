@@ -0,0 +1,167 @@
+package gonfig_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/axkit/gonfig"
+)
+
+func TestConfig_OnChange(t *testing.T) {
+
+	type store struct {
+		Port    gonfig.Int         `cfg:"port" default:"80"`
+		Timeout gonfig.Duration    `cfg:"timeout" default:"1s"`
+		Hosts   gonfig.StringSlice `cfg:"hosts" default:"a,b"`
+	}
+
+	var a, b store
+	cfg := gonfig.New()
+	cfg.BindStruct(&a)
+	cfg.BindStruct(&b)
+
+	if _, err := cfg.OnChange("unknown", func(old, new string) {}); err != gonfig.ErrNotFound {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+
+	var changes []string
+	cancel, err := cfg.OnChange("port", func(old, new string) {
+		changes = append(changes, old+">"+new)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var hosts []string
+	cancelHosts, err := cfg.OnChange("hosts", func(old, new string) {
+		hosts = append(hosts, old+">"+new)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cancelHosts()
+
+	a.Port.Set(8080)
+	a.Port.Set(8080) // not changed
+	if err := b.Port.Parse("8081"); err != nil {
+		t.Error(err)
+	}
+	p, _ := cfg.Get("port")
+	p.Parse("8082")
+
+	b.Hosts.Set([]string{"c"})
+	b.Hosts.Set([]string{"c"}) // not changed
+	b.Hosts.Set([]string{"c,d"})
+	b.Hosts.Set([]string{"c", "d"}) // same joined, but items differ
+
+	cancel()
+	cancel()
+	a.Port.Set(1)
+
+	if len(changes) != 3 || changes[0] != "80>8080" || changes[1] != "8080>8081" || changes[2] != "8081>8082" {
+		t.Errorf("unexpected changes: %v", changes)
+	}
+
+	if len(hosts) != 3 || hosts[0] != `["a","b"]>["c"]` || hosts[1] != `["c"]>["c,d"]` || hosts[2] != `["c,d"]>["c","d"]` {
+		t.Errorf("unexpected changes: %v", hosts)
+	}
+}
+
+func TestConfig_OnChangeReentrant(t *testing.T) {
+
+	cfg := gonfig.New()
+	var port gonfig.Int
+	cfg.BindVar("port", &port)
+
+	var changes []string
+	cancel, _ := cfg.OnChange("port", func(old, new string) {
+		changes = append(changes, new)
+		if new == "1" {
+			port.Set(2)
+		}
+	})
+	defer cancel()
+
+	port.Set(1)
+
+	if len(changes) != 2 || changes[0] != "1" || changes[1] != "2" {
+		t.Errorf("unexpected changes: %v", changes)
+	}
+}
+
+func TestConfig_Watch(t *testing.T) {
+
+	cfg := gonfig.New()
+	var listen gonfig.String
+	cfg.BindVar("listen", &listen)
+
+	ch, cancel, err := cfg.Watch("listen")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	listen.Set("127.0.0.1")
+	select {
+	case c := <-ch:
+		if c.Code != "listen" || c.Old != "" || c.New != "127.0.0.1" {
+			t.Errorf("unexpected change: %+v", c)
+		}
+	case <-time.After(time.Second):
+		t.Error("change is not delivered")
+	}
+
+	for i := 0; i < gonfig.DefaultWatchBuffer+5; i++ {
+		listen.Set(string(rune('a' + i)))
+	}
+
+	var last gonfig.Change
+	for i := 0; i < gonfig.DefaultWatchBuffer; i++ {
+		last = <-ch
+	}
+	if last.New != listen.Val() {
+		t.Errorf("the last change must be kept, got %+v", last)
+	}
+
+	cancel()
+	if _, ok := <-ch; ok {
+		t.Error("channel must be closed")
+	}
+	listen.Set("after cancel")
+}
+
+func TestConfig_OnChangeConcurrent(t *testing.T) {
+
+	cfg := gonfig.New()
+	var counter gonfig.Int
+	cfg.BindVar("counter", &counter)
+
+	var mux sync.Mutex
+	last := "0"
+	var broken bool
+	cancel, _ := cfg.OnChange("counter", func(old, new string) {
+		mux.Lock()
+		defer mux.Unlock()
+		if old != last {
+			broken = true
+		}
+		last = new
+	})
+	defer cancel()
+
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 1; i <= 100; i++ {
+				counter.Set(g*1000 + i)
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	if broken {
+		t.Error("changes are delivered out of order")
+	}
+}
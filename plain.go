@@ -0,0 +1,211 @@
+package gonfig
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	durationType        = reflect.TypeOf(time.Duration(0))
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// plainKind returns kind of param holding value of plain field
+// of type t. Returns Unknown if type is not supported.
+func plainKind(t reflect.Type) AKind {
+	if reflect.PtrTo(t).Implements(textUnmarshalerType) {
+		return AString
+	}
+	if t == durationType {
+		return ADuration
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		return plainKind(t.Elem())
+	case reflect.Bool:
+		return ABool
+	case reflect.String:
+		return AString
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return AInt
+	case reflect.Float32, reflect.Float64:
+		return AFloat
+	case reflect.Slice:
+		switch et := t.Elem(); {
+		case plainKind(et) == Unknown || et.Kind() == reflect.Slice:
+			return Unknown
+		case et.Kind() == reflect.String && !reflect.PtrTo(et).Implements(textUnmarshalerType):
+			return AStringSlice
+		case et.Kind() == reflect.Int:
+			return AIntSlice
+		}
+		return AString
+	}
+	return Unknown
+}
+
+// bindPlain populates field fv not implementing Valuer once by value
+// of param identified by code. Param is added into container
+// as static one if it's not containerized yet and tag has no
+// key static. Field keeps its value
+// if param was not initialized before and has no default value.
+// Must be called with locked c.mux.
+func (c *Config) bindPlain(code string, name string, fv reflect.Value, tag reflect.StructTag) []error {
+	if !fv.CanSet() {
+		return []error{fmt.Errorf("param '%s': field %s is not exported", code, name)}
+	}
+
+	ak := plainKind(fv.Type())
+	if ak == Unknown {
+		return []error{fmt.Errorf("param '%s': field %s has unsupported type %s", code, name, fv.Type())}
+	}
+
+	idx, wasinit := c.idx[code]
+	if wasinit {
+		ak = c.list[idx].av.Kind()
+	}
+
+	// param shared with other fields keeps its static flag.
+	if _, ok := tag.Lookup("static"); !ok && !wasinit {
+		tag = reflect.StructTag(string(tag) + ` static:"true"`)
+	}
+
+	res := c.bindField(code, makeValuer(ak), tag)
+	idx, ok := c.idx[code]
+	if !ok || !wasinit && tag.Get("default") == "" {
+		return res
+	}
+
+	s, err := exact(c.list[idx].av)
+	if err == nil {
+		err = setPlain(fv, s, tag.Get("sep"))
+	}
+	if err != nil {
+		res = append(res, fmt.Errorf("param '%s': can't assign %q to field %s of type %s: %w", code, s, name, fv.Type(), err))
+	}
+	return res
+}
+
+// setPlain converts s and assigns it to v. Slice items are separated
+// by sep or given as JSON array.
+func setPlain(v reflect.Value, s, sep string) error {
+	if v.Kind() == reflect.Ptr {
+		n := reflect.New(v.Type().Elem())
+		if err := setPlain(n.Elem(), s, sep); err != nil {
+			return err
+		}
+		v.Set(n)
+		return nil
+	}
+
+	if u, ok := v.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		return u.UnmarshalText([]byte(s))
+	}
+
+	s = strings.TrimSpace(s)
+	if v.Type() == durationType {
+		var d time.Duration
+		if s != "" {
+			var err error
+			if d, err = time.ParseDuration(s); err != nil {
+				return err
+			}
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		if s == "" {
+			v.SetBool(false)
+			return nil
+		}
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if s == "" {
+			v.SetInt(0)
+			return nil
+		}
+		i, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if s == "" {
+			v.SetUint(0)
+			return nil
+		}
+		u, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		if s == "" {
+			v.SetFloat(0)
+			return nil
+		}
+		f, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	case reflect.Slice:
+		items, err := plainItems(s, sep)
+		if err != nil {
+			return err
+		}
+		sl := reflect.MakeSlice(v.Type(), len(items), len(items))
+		for i, item := range items {
+			if err := setPlain(sl.Index(i), item, sep); err != nil {
+				return err
+			}
+		}
+		v.Set(sl)
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+	return nil
+}
+
+// plainItems splits s by sep or decodes it as JSON array.
+func plainItems(s, sep string) ([]string, error) {
+	if !isJSONArray(s) {
+		if sep == "" {
+			sep = DefaultSliceSeparator
+		}
+		return splitSlice(s, sep), nil
+	}
+
+	var raw []json.RawMessage
+	if err := json.Unmarshal([]byte(s), &raw); err != nil {
+		return nil, err
+	}
+	res := make([]string, len(raw))
+	for i, r := range raw {
+		if bytes.HasPrefix(r, []byte(`"`)) {
+			if err := json.Unmarshal(r, &res[i]); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		res[i] = string(r)
+	}
+	return res, nil
+}
@@ -0,0 +1,116 @@
+package gonfig_test
+
+import (
+	"context"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/axkit/gonfig"
+)
+
+type plainConfig struct {
+	Port    int           `cfg:"port" default:"8080"`
+	Debug   bool          `cfg:"debug"`
+	Ratio   float64       `cfg:"ratio" default:"0.25"`
+	Name    string        `cfg:"name" default:"svc"`
+	Timeout time.Duration `cfg:"timeout" default:"5s"`
+	Hosts   []string      `cfg:"hosts" default:"a;b" sep:";"`
+	Codes   []uint16      `cfg:"codes"`
+	IP      net.IP        `cfg:"ip"`
+	Limit   *int          `cfg:"limit"`
+	Keep    string        `cfg:"keep"`
+}
+
+func TestConfig_BindStruct_Plain(t *testing.T) {
+
+	cfg := gonfig.New()
+	cfg.MustParam("debug", gonfig.AString).Parse("true")
+	cfg.MustParam("codes", gonfig.AString).Parse("200, 404")
+	cfg.MustParam("ip", gonfig.AString).Parse("10.0.0.1")
+	cfg.MustParam("limit", gonfig.AInt).Parse("3")
+	cfg.MustParam("port", gonfig.AInt).Parse("9090")
+
+	c := plainConfig{Keep: "kept"}
+	if errs := cfg.BindStruct(&c); len(errs) > 0 {
+		t.Fatal(errs)
+	}
+
+	if c.Port != 9090 || !c.Debug || c.Ratio != 0.25 || c.Name != "svc" || c.Timeout != 5*time.Second {
+		t.Errorf("unexpected values %d %v %v %q %v", c.Port, c.Debug, c.Ratio, c.Name, c.Timeout)
+	}
+	if !reflect.DeepEqual(c.Hosts, []string{"a", "b"}) || !reflect.DeepEqual(c.Codes, []uint16{200, 404}) {
+		t.Errorf("unexpected slices %v %v", c.Hosts, c.Codes)
+	}
+	if !c.IP.Equal(net.IPv4(10, 0, 0, 1)) || c.Limit == nil || *c.Limit != 3 {
+		t.Errorf("unexpected values %v %v", c.IP, c.Limit)
+	}
+	if c.Keep != "kept" {
+		t.Errorf("expected field value kept, got %q", c.Keep)
+	}
+
+	// params created by binding are containerized as static ones,
+	// existing params keep own static flag.
+	info, ok := cfg.Info("port")
+	if !ok || info.Kind != gonfig.AInt || info.Static || info.Asked != 1 {
+		t.Errorf("unexpected info %+v", info)
+	}
+	if info, _ := cfg.Info("ratio"); !info.Static {
+		t.Errorf("unexpected info %+v", info)
+	}
+	if info, _ := cfg.Info("hosts"); info.Kind != gonfig.AStringSlice {
+		t.Errorf("unexpected kind %s", info.Kind)
+	}
+
+	// later changes don't reach the field.
+	cfg.MustParam("port", gonfig.AInt).Parse("1")
+	if c.Port != 9090 {
+		t.Errorf("expected 9090, got %d", c.Port)
+	}
+}
+
+func TestConfig_BindStruct_PlainShared(t *testing.T) {
+
+	var c struct {
+		Port gonfig.Int `cfg:"port" default:"80"`
+		Raw  int        `cfg:"port"`
+		Name string     `cfg:"name" static:"false"`
+	}
+	cfg := gonfig.New()
+	if errs := cfg.BindStruct(&c); len(errs) > 0 {
+		t.Fatal(errs)
+	}
+	cfg.Freeze()
+
+	// param shared with Valuer field is not frozen by plain field.
+	if err := cfg.ParseContext(context.Background(), "port", "81"); err != nil || c.Port.Val() != 81 || c.Raw != 80 {
+		t.Errorf("unexpected values %d %d %v", c.Port.Val(), c.Raw, err)
+	}
+	if info, _ := cfg.Info("name"); info.Static {
+		t.Errorf("unexpected info %+v", info)
+	}
+}
+
+func TestConfig_BindStruct_PlainErrors(t *testing.T) {
+
+	cfg := gonfig.New()
+	cfg.MustParam("port", gonfig.AString).Parse("abc")
+	cfg.MustParam("small", gonfig.AInt).Parse("300")
+
+	var c struct {
+		Port    int               `cfg:"port"`
+		Small   int8              `cfg:"small"`
+		Wait    time.Duration     `cfg:"wait" default:"5"`
+		Labels  map[string]string `cfg:"labels"`
+		private int               `cfg:"private"`
+		Valid   int               `cfg:"valid" default:"1"`
+	}
+	errs := cfg.BindStruct(&c)
+	if len(errs) != 5 {
+		t.Fatalf("expected 5 errors, got %v", errs)
+	}
+	if c.Port != 0 || c.Small != 0 || c.Valid != 1 || c.private != 0 {
+		t.Errorf("unexpected values %d %d %d", c.Port, c.Small, c.Valid)
+	}
+}
@@ -0,0 +1,169 @@
+package gonfig
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DefaultPrefixSeparator joins prefixes of nested structs and codes
+// if separator was not set by WithPrefixSeparator.
+var DefaultPrefixSeparator = "."
+
+// WithPrefixSeparator sets separator joining prefixes of nested
+// structs and codes.
+func (c *Config) WithPrefixSeparator(sep string) *Config {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	c.psep = sep
+	return c
+}
+
+// prefixSep returns separator joining prefixes and codes.
+func (c *Config) prefixSep() string {
+	if c.psep != "" {
+		return c.psep
+	}
+	return DefaultPrefixSeparator
+}
+
+// parseTag returns code from tag "cfg" and true if the tag
+// has option prefix.
+func parseTag(tag string) (string, bool) {
+	code, opts, _ := strings.Cut(tag, ",")
+	for _, opt := range strings.Split(opts, ",") {
+		if strings.TrimSpace(opt) == "prefix" {
+			return code, true
+		}
+	}
+	return code, false
+}
+
+// bindNested binds fields of struct, slice or map of structs fv
+// to params having codes with prefix.
+// Must be called with locked c.mux.
+func (c *Config) bindNested(prefix, name string, fv reflect.Value) []error {
+	t := fv.Type()
+	switch {
+	case isStruct(t):
+		return c.bindElem(prefix, fv)
+	case t.Kind() == reflect.Slice && isStruct(t.Elem()):
+		return c.bindSlice(prefix, name, fv)
+	case t.Kind() == reflect.Map && t.Key().Kind() == reflect.String && isStruct(t.Elem()):
+		return c.bindMap(prefix, fv)
+	}
+	return []error{fmt.Errorf("prefix '%s': field %s of type %s must be struct, slice or map of structs", prefix, name, t)}
+}
+
+// isStruct returns true if t is struct or pointer to struct.
+func isStruct(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.Struct
+}
+
+// bindElem binds struct or pointer to struct v, allocating
+// nil pointer.
+func (c *Config) bindElem(prefix string, v reflect.Value) []error {
+	if v.Kind() != reflect.Ptr {
+		return c.bindStruct(prefix, v.Addr().Interface())
+	}
+	if v.IsNil() {
+		v.Set(reflect.New(v.Type().Elem()))
+	}
+	return c.bindStruct(prefix, v.Interface())
+}
+
+// bindSlice binds items of slice v to params prefix+index+sep+code.
+// Slice is extended by consecutive indexes found in container,
+// indexes after a gap are reported as errors and not bound.
+func (c *Config) bindSlice(prefix, name string, v reflect.Value) []error {
+	var res []error
+	var idxs []int
+	found := make(map[int]bool)
+	for _, key := range c.keys(prefix) {
+		i, err := strconv.Atoi(key)
+		if err != nil || i < 0 || strconv.Itoa(i) != key {
+			res = append(res, fmt.Errorf("param '%s%s': field %s expects index", prefix, key, name))
+			continue
+		}
+		idxs = append(idxs, i)
+		found[i] = true
+	}
+	sort.Ints(idxs)
+
+	n := v.Len()
+	for found[n] {
+		n++
+	}
+	for _, i := range idxs {
+		if i > n {
+			res = append(res, fmt.Errorf("param '%s%d': field %s expects consecutive indexes, index %d is missing", prefix, i, name, n))
+		}
+	}
+
+	if n > v.Len() {
+		sl := reflect.MakeSlice(v.Type(), n, n)
+		reflect.Copy(sl, v)
+		v.Set(sl)
+	}
+
+	for i := 0; i < n; i++ {
+		res = append(res, c.bindElem(prefix+strconv.Itoa(i)+c.prefixSep(), v.Index(i))...)
+	}
+	return res
+}
+
+// bindMap binds items of map v to params prefix+key+sep+code.
+// Map gets items for keys found in container.
+func (c *Config) bindMap(prefix string, v reflect.Value) []error {
+	if v.IsNil() {
+		v.Set(reflect.MakeMap(v.Type()))
+	}
+
+	keys := c.keys(prefix)
+	for _, k := range v.MapKeys() {
+		keys = append(keys, k.String())
+	}
+	sort.Strings(keys)
+
+	var res []error
+	for i, key := range keys {
+		if i > 0 && keys[i-1] == key {
+			continue
+		}
+
+		k := reflect.ValueOf(key).Convert(v.Type().Key())
+		item := reflect.New(v.Type().Elem()).Elem()
+		if old := v.MapIndex(k); old.IsValid() {
+			item.Set(old)
+		}
+		res = append(res, c.bindElem(prefix+key+c.prefixSep(), item)...)
+		v.SetMapIndex(k, item)
+	}
+	return res
+}
+
+// keys returns sorted unique parts of codes between prefix and
+// the next separator. Must be called with locked c.mux.
+func (c *Config) keys(prefix string) []string {
+	seen := make(map[string]bool)
+	var res []string
+	for code := range c.idx {
+		rest := strings.TrimPrefix(code, prefix)
+		if rest == code {
+			continue
+		}
+		key, _, ok := strings.Cut(rest, c.prefixSep())
+		if !ok || key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		res = append(res, key)
+	}
+	sort.Strings(res)
+	return res
+}
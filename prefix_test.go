@@ -0,0 +1,113 @@
+package gonfig_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/axkit/gonfig"
+)
+
+type prefixListener struct {
+	Port   gonfig.Int    `cfg:"port" default:"80"`
+	Listen gonfig.String `cfg:"listen"`
+}
+
+type prefixServer struct {
+	Host string     `cfg:"host"`
+	Port gonfig.Int `cfg:"port"`
+}
+
+type prefixConfig struct {
+	Public  prefixListener           `cfg:"public,prefix"`
+	Admin   *prefixListener          `cfg:"admin,prefix"`
+	Servers []prefixServer           `cfg:"servers,prefix"`
+	Regions map[string]*prefixServer `cfg:"regions,prefix"`
+}
+
+func TestConfig_BindStruct_Prefix(t *testing.T) {
+
+	cfg := gonfig.New()
+	cfg.MustParam("admin.port", gonfig.AInt).Parse("8081")
+	cfg.MustParam("servers.1.host", gonfig.AString).Parse("b")
+	cfg.MustParam("servers.0.port", gonfig.AInt).Parse("5432")
+	cfg.MustParam("regions.eu.port", gonfig.AInt).Parse("443")
+
+	c := prefixConfig{Regions: map[string]*prefixServer{"us": {Host: "us.local"}}}
+	if errs := cfg.BindStruct(&c); len(errs) > 0 {
+		t.Fatal(errs)
+	}
+
+	if c.Public.Port.Val() != 80 || c.Admin == nil || c.Admin.Port.Val() != 8081 {
+		t.Errorf("unexpected listeners %d %v", c.Public.Port.Val(), c.Admin)
+	}
+	if !cfg.IsExist("public.listen") || !cfg.IsExist("admin.listen") {
+		t.Error("expected prefixed params")
+	}
+
+	if len(c.Servers) != 2 || c.Servers[0].Port.Val() != 5432 || c.Servers[1].Host != "b" {
+		t.Errorf("unexpected servers %d", len(c.Servers))
+	}
+
+	if len(c.Regions) != 2 || c.Regions["eu"].Port.Val() != 443 || c.Regions["us"].Host != "us.local" {
+		t.Errorf("unexpected regions %d", len(c.Regions))
+	}
+	if !cfg.IsExist("regions.us.port") {
+		t.Error("expected param of existing map item")
+	}
+
+	// items address the same memory as params.
+	cfg.MustParam("servers.0.port", gonfig.AInt).Parse("5433")
+	cfg.MustParam("regions.eu.port", gonfig.AInt).Parse("8443")
+	if c.Servers[0].Port.Val() != 5433 || c.Regions["eu"].Port.Val() != 8443 {
+		t.Errorf("expected changes, got %d %d", c.Servers[0].Port.Val(), c.Regions["eu"].Port.Val())
+	}
+}
+
+func TestConfig_WithPrefixSeparator(t *testing.T) {
+
+	cfg := gonfig.New().WithPrefixSeparator("_")
+	cfg.MustParam("servers_0_port", gonfig.AInt).Parse("1")
+
+	var c struct {
+		Admin   prefixListener `cfg:"admin,prefix"`
+		Servers []prefixServer `cfg:"servers,prefix"`
+	}
+	if errs := cfg.BindStruct(&c); len(errs) > 0 {
+		t.Fatal(errs)
+	}
+	if !cfg.IsExist("admin_port") || len(c.Servers) != 1 || c.Servers[0].Port.Val() != 1 {
+		t.Errorf("unexpected binding %d", len(c.Servers))
+	}
+}
+
+func TestConfig_BindStruct_PrefixErrors(t *testing.T) {
+
+	cfg := gonfig.New()
+	cfg.MustParam("servers.x.port", gonfig.AInt)
+
+	var c struct {
+		Servers []prefixServer `cfg:"servers,prefix"`
+		Ports   []int          `cfg:"ports,prefix"`
+	}
+	if errs := cfg.BindStruct(&c); len(errs) != 2 {
+		t.Errorf("expected 2 errors, got %v", errs)
+	}
+}
+
+func TestConfig_BindStruct_PrefixSparse(t *testing.T) {
+
+	cfg := gonfig.New()
+	cfg.MustParam("servers.0.port", gonfig.AInt).Parse("1")
+	cfg.MustParam("servers.99999.port", gonfig.AInt).Parse("2")
+
+	var c struct {
+		Servers []prefixServer `cfg:"servers,prefix"`
+	}
+	errs := cfg.BindStruct(&c)
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "servers.99999") {
+		t.Errorf("expected error of index 99999, got %v", errs)
+	}
+	if len(c.Servers) != 1 || c.Servers[0].Port.Val() != 1 || cfg.IsExist("servers.1.port") {
+		t.Errorf("unexpected servers %d", len(c.Servers))
+	}
+}
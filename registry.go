@@ -0,0 +1,122 @@
+package gonfig
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// firstCustomKind is the first AKind assigned by RegisterKind.
+// Kinds below are reserved for built-in valuers.
+const firstCustomKind AKind = 32
+
+// kindDesc describes a kind registered by RegisterKind.
+type kindDesc struct {
+	kind   AKind
+	name   string
+	typ    reflect.Type
+	new    func() Valuer
+	parse  interface{}
+	format interface{}
+}
+
+var registry = struct {
+	mux    sync.RWMutex
+	next   AKind
+	byKind map[AKind]*kindDesc
+	byType map[reflect.Type]*kindDesc
+}{
+	next:   firstCustomKind,
+	byKind: make(map[AKind]*kindDesc),
+	byType: make(map[reflect.Type]*kindDesc),
+}
+
+// RegisterKind registers a custom kind of parameters holding values
+// of type T and returns its AKind. Parameters of the kind are
+// represented by *Value[T]. If format is nil, fmt.Sprint is used.
+//
+// RegisterKind is expected to be called from package init.
+// Panics if type T or name already registered, or parse is nil.
+//
+// Example
+//
+//	var AIP = gonfig.RegisterKind("AIP", func(s string) (net.IP, error) {
+//		ip := net.ParseIP(s)
+//		if ip == nil {
+//			return nil, errors.New("invalid ip address")
+//		}
+//		return ip, nil
+//	}, net.IP.String)
+func RegisterKind[T any](name string, parse func(string) (T, error), format func(T) string) AKind {
+	if parse == nil {
+		panic("gonfig: RegisterKind parse is nil")
+	}
+
+	if format == nil {
+		format = func(v T) string { return fmt.Sprint(v) }
+	}
+
+	typ := reflect.TypeOf((*T)(nil)).Elem()
+
+	registry.mux.Lock()
+	defer registry.mux.Unlock()
+
+	if _, ok := registry.byType[typ]; ok {
+		panic("gonfig: RegisterKind called twice for type " + typ.String())
+	}
+
+	for _, d := range registry.byKind {
+		if d.name == name {
+			panic("gonfig: RegisterKind called twice for kind " + name)
+		}
+	}
+
+	if registry.next < firstCustomKind {
+		panic("gonfig: too many registered kinds")
+	}
+
+	d := &kindDesc{
+		kind:   registry.next,
+		name:   name,
+		typ:    typ,
+		new:    func() Valuer { return NewValue[T]() },
+		parse:  parse,
+		format: format,
+	}
+	registry.next++
+	registry.byKind[d.kind] = d
+	registry.byType[typ] = d
+	return d.kind
+}
+
+func kindByCode(ak AKind) (*kindDesc, bool) {
+	registry.mux.RLock()
+	defer registry.mux.RUnlock()
+	d, ok := registry.byKind[ak]
+	return d, ok
+}
+
+func kindByType(typ reflect.Type) (*kindDesc, bool) {
+	registry.mux.RLock()
+	defer registry.mux.RUnlock()
+	d, ok := registry.byType[typ]
+	return d, ok
+}
+
+// kindByName returns built-in or registered kind named s.
+func kindByName(s string) (AKind, bool) {
+	for ak := AInt; ak <= lastKind; ak++ {
+		if ak.String() == s {
+			return ak, true
+		}
+	}
+
+	registry.mux.RLock()
+	defer registry.mux.RUnlock()
+	for _, d := range registry.byKind {
+		if d.name == s {
+			return d.kind, true
+		}
+	}
+	return Unknown, false
+}
@@ -0,0 +1,154 @@
+package gonfig
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ErrUnknownParam indicates that source provided param which
+// was never bound.
+var ErrUnknownParam = errors.New("unknown param")
+
+// AuditReport describes params likely misconfigured. It's
+// returned by Audit.
+type AuditReport struct {
+	// Unbound are params provided by sources but never bound
+	// nor referenced by other params, likely misspelled codes.
+	Unbound []UnboundParam
+
+	// Defaulted are codes of params bound but never provided
+	// by any source. They have default or zero values.
+	Defaulted []string
+}
+
+// UnboundParam is a param provided by source but never bound.
+type UnboundParam struct {
+	Code string
+
+	// Origin is a source provided the param. It's empty if param
+	// was not provided by Loader.
+	Origin Origin
+
+	// Similar is the most similar code of bound param if any.
+	Similar string
+}
+
+// Audit returns params provided by sources but never bound and
+// params bound but never provided. It's intended to be called at
+// startup after all structs are bound.
+func (c *Config) Audit() AuditReport {
+	refs := c.referenced()
+
+	c.mux.RLock()
+	defer c.mux.RUnlock()
+
+	var bound []string
+	for i := range c.list {
+		if c.list[i].asked > 0 {
+			bound = append(bound, c.list[i].code)
+		}
+	}
+
+	var res AuditReport
+	for i := range c.list {
+		p := &c.list[i]
+		switch {
+		case p.asked == 0 && p.provided() && !refs[p.code]:
+			u := UnboundParam{Code: p.code, Similar: similar(p.code, bound)}
+			if p.origin != nil {
+				u.Origin = *p.origin
+			}
+			res.Unbound = append(res.Unbound, u)
+		case p.asked > 0 && !p.provided():
+			res.Defaulted = append(res.Defaulted, p.code)
+		}
+	}
+
+	sort.Slice(res.Unbound, func(i, j int) bool {
+		return res.Unbound[i].Code < res.Unbound[j].Code
+	})
+	sort.Strings(res.Defaulted)
+	return res
+}
+
+// Err returns error wrapping ErrUnknownParam if any of unbound
+// params was provided by Loader, for example by file or environment
+// source. It makes strict startup:
+//
+//	if err := cfg.Audit().Err(); err != nil {
+//		log.Fatal(err)
+//	}
+func (r AuditReport) Err() error {
+	var msgs []string
+	for _, p := range r.Unbound {
+		if p.Origin.Source == "" {
+			continue
+		}
+		msg := fmt.Sprintf("'%s' from %s", p.Code, p.Origin)
+		if p.Similar != "" {
+			msg += fmt.Sprintf(", did you mean '%s'?", p.Similar)
+		}
+		msgs = append(msgs, msg)
+	}
+	if len(msgs) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%w: %s", ErrUnknownParam, strings.Join(msgs, "; "))
+}
+
+// referenced returns codes referenced by interpolated values of params.
+func (c *Config) referenced() map[string]bool {
+	res := make(map[string]bool)
+	c.expanders.Range(func(_, v interface{}) bool {
+		e := v.(*expander)
+		e.mux.Lock()
+		for _, ref := range e.refs {
+			res[ref] = true
+		}
+		e.mux.Unlock()
+		return true
+	})
+	return res
+}
+
+// similar returns code from codes having the smallest edit distance
+// to s, not greater than 2. Returns empty string if none.
+func similar(s string, codes []string) string {
+	var res string
+	best := 3
+	for _, code := range codes {
+		if d := distance(s, code); d < best {
+			res, best = code, d
+		}
+	}
+	return res
+}
+
+// distance returns Levenshtein distance between a and b.
+func distance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
+
+// provided returns true if value of param was provided by source,
+// it's created by Param, MustParam or has origin.
+func (p *param) provided() bool {
+	return p.inited > 0 || p.origin != nil
+}
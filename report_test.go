@@ -0,0 +1,113 @@
+package gonfig_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/axkit/gonfig"
+)
+
+type reportConfig struct {
+	Port    gonfig.Int    `cfg:"port" default:"80"`
+	Listen  gonfig.String `cfg:"listen" default:"0.0.0.0"`
+	Timeout gonfig.Int    `cfg:"timeout"`
+	URL     gonfig.String `cfg:"url"`
+}
+
+func TestConfig_Audit(t *testing.T) {
+
+	l := gonfig.NewLoader().Add("env", gonfig.MapSource{
+		"por":       "8080",
+		"listen":    "127.0.0.1",
+		"host":      "example.com",
+		"url":       "https://${host}",
+		"unrelated": "x",
+	})
+
+	cfg := gonfig.New().WithInterpolation()
+	if err := l.Load(cfg); err != nil {
+		t.Fatal(err)
+	}
+	cfg.MustParam("local", gonfig.AInt)
+
+	var c reportConfig
+	if errs := cfg.BindStruct(&c); len(errs) > 0 {
+		t.Fatal(errs)
+	}
+
+	r := cfg.Audit()
+	if len(r.Unbound) != 3 {
+		t.Fatalf("expected 3 unbound params, got %+v", r.Unbound)
+	}
+	if u := r.Unbound[1]; u.Code != "por" || u.Similar != "port" || u.Origin.Source != "env" {
+		t.Errorf("unexpected unbound param %+v", u)
+	}
+	if u := r.Unbound[0]; u.Code != "local" || u.Origin.Source != "" {
+		t.Errorf("unexpected unbound param %+v", u)
+	}
+	if u := r.Unbound[2]; u.Code != "unrelated" || u.Similar != "" {
+		t.Errorf("unexpected unbound param %+v", u)
+	}
+	if strings.Join(r.Defaulted, ",") != "port,timeout" {
+		t.Errorf("unexpected defaulted params %v", r.Defaulted)
+	}
+
+	err := r.Err()
+	if !errors.Is(err, gonfig.ErrUnknownParam) || !strings.Contains(err.Error(), "did you mean 'port'?") || strings.Contains(err.Error(), "local") {
+		t.Errorf("unexpected error %v", err)
+	}
+
+	if err := cfg.Sub("x").(view).Audit().Err(); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func TestConfig_Audit_Sub(t *testing.T) {
+
+	cfg := gonfig.New()
+	cfg.MustParam("db.hots", gonfig.AString)
+
+	var c struct {
+		Host gonfig.String `cfg:"host"`
+	}
+	db := cfg.Sub("db").(view)
+	if errs := db.BindStruct(&c); len(errs) > 0 {
+		t.Fatal(errs)
+	}
+
+	r := db.Audit()
+	if len(r.Unbound) != 1 || r.Unbound[0].Code != "hots" || r.Unbound[0].Similar != "host" {
+		t.Errorf("unexpected unbound params %+v", r.Unbound)
+	}
+	if len(r.Defaulted) != 1 || r.Defaulted[0] != "host" {
+		t.Errorf("unexpected defaulted params %v", r.Defaulted)
+	}
+	if r.Err() != nil {
+		t.Errorf("unexpected error %v", r.Err())
+	}
+}
+
+func TestConfig_Audit_Update(t *testing.T) {
+
+	cfg := gonfig.New()
+	var c reportConfig
+	if errs := cfg.BindStruct(&c); len(errs) > 0 {
+		t.Fatal(errs)
+	}
+
+	ctx := gonfig.WithSource(context.Background(), "http")
+	if err := cfg.UpdateContext(ctx, func(tx gonfig.Tx) error {
+		return tx.Parse("timeout", "30")
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if o, ok := cfg.Origin("timeout"); !ok || o.Source != "http" {
+		t.Errorf("unexpected origin %v", o)
+	}
+	if r := cfg.Audit(); strings.Join(r.Defaulted, ",") != "listen,port,url" {
+		t.Errorf("unexpected defaulted params %v", r.Defaulted)
+	}
+}
@@ -0,0 +1,186 @@
+package gonfig
+
+import (
+	"strconv"
+	"sync/atomic"
+	"unsafe"
+)
+
+// SecretMask replaces value of non empty Secret in String,
+// MarshalJSON and fmt output.
+var SecretMask = "******"
+
+// Secret implements atomic string which value is never shown.
+// Value is available by explicit Reveal call only.
+type Secret struct {
+	ref *string
+
+	// c is container the param belongs to.
+	c *Config
+}
+
+// NewSecret returns atomic secret implemented as atomic ptr.
+func NewSecret() *Secret {
+	return &Secret{ref: new(string)}
+}
+
+// Kind returns ASecret.
+func (a *Secret) Kind() AKind {
+	return ASecret
+}
+
+// Set assigns value atomically. Initializes if was not before.
+// OnChange subscribers receive masked values.
+func (a *Secret) Set(s string) {
+	if a.owner().rejected(a.mem(), func() Valuer {
+		n := NewSecret()
+		n.Set(s)
+		return n
+	}) {
+		return
+	}
+	a.set(s, nil)
+}
+
+// set is like Set, the change is attributed to by.
+func (a *Secret) set(s string, by *author) {
+	sp := new(string)
+	*sp = s
+	ptr := atomic.LoadPointer((*unsafe.Pointer)(unsafe.Pointer(&a.ref)))
+	if n := a.owner().notifierOf(ptr); n != nil {
+		n.changeSecret(by, func() (string, string, bool) {
+			old := NonBindedString
+			if op := atomic.SwapPointer((*unsafe.Pointer)(ptr), (unsafe.Pointer)(sp)); op != nil {
+				old = *(*string)(op)
+			}
+			return old, s, old != s
+		})
+		return
+	}
+	if ptr != nil {
+		atomic.StorePointer((*unsafe.Pointer)(ptr), (unsafe.Pointer)(sp))
+		return
+	}
+
+	n := NewSecret()
+	a.Bind(n)
+	atomic.StorePointer((*unsafe.Pointer)(unsafe.Pointer(n.ref)), (unsafe.Pointer)(sp))
+}
+
+// Reveal returns value atomically. Returns NonBindedString
+// if it's not binded to params container.
+func (a *Secret) Reveal() string {
+	ptr := atomic.LoadPointer((*unsafe.Pointer)(unsafe.Pointer(&a.ref)))
+	if ptr == nil {
+		return NonBindedString
+	}
+	s := atomic.LoadPointer((*unsafe.Pointer)(unsafe.Pointer(ptr)))
+	if s == nil {
+		return NonBindedString
+	}
+	return *(*string)(s)
+}
+
+// IsBinded returns true if Secret bineded to params container.
+func (a *Secret) IsBinded() bool {
+	return atomic.LoadPointer((*unsafe.Pointer)(unsafe.Pointer(&a.ref))) != nil
+}
+
+func (a *Secret) mem() unsafe.Pointer {
+	return atomic.LoadPointer((*unsafe.Pointer)(unsafe.Pointer(&a.ref)))
+}
+
+func (a *Secret) owner() *Config {
+	return loadOwner(&a.c)
+}
+
+func (a *Secret) own(c *Config) {
+	storeOwner(&a.c, c)
+}
+
+func (a *Secret) assign(v Valuer, by *author) {
+	a.set(v.(*Secret).Reveal(), by)
+}
+
+// Bind binds current atomic variable to variable identified by to.
+// As a result two Secret address to the same variable.
+func (a *Secret) Bind(to *Secret) {
+	ptr := atomic.LoadPointer((*unsafe.Pointer)(unsafe.Pointer(&to.ref)))
+	if ptr != nil {
+		storeOwner(&a.c, to.owner())
+		atomic.StorePointer((*unsafe.Pointer)(unsafe.Pointer(&a.ref)), ptr)
+	}
+}
+
+// String implements Stringer interface. Returns SecretMask
+// or empty string if value is empty.
+func (a *Secret) String() string {
+	return mask(a.Reveal())
+}
+
+// GoString implements GoStringer interface used by %#v.
+func (a *Secret) GoString() string {
+	return strconv.Quote(a.String())
+}
+
+// MarshalJSON implement Marshaller interface. Value is masked.
+func (a Secret) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Quote(a.String())), nil
+}
+
+// UnmarshalJSON implement Unmarshaller interface.
+func (a *Secret) UnmarshalJSON(buf []byte) error {
+	v, err := strconv.Unquote(string(buf))
+	if err != nil {
+		return err
+	}
+	a.Set(v)
+	return nil
+}
+
+// Parse implements Valuer interface. Calls Set.
+// Value is not changed if it violates validation rules.
+// References are resolved as by String.Parse.
+func (a *Secret) Parse(s string) error {
+	e := a.owner().expanderOf(a.mem())
+	tmpl := s
+	if e != nil {
+		var err error
+		if s, err = e.expand(s); err != nil {
+			return err
+		}
+	}
+
+	if vr := a.owner().validatorOf(a.mem()); vr != nil {
+		n := NewSecret()
+		n.Set(s)
+		if err := vr.check(n); err != nil {
+			return err
+		}
+	}
+	a.Set(s)
+
+	if e != nil {
+		e.commit(tmpl)
+	}
+	return nil
+}
+
+func mask(s string) string {
+	if s == "" {
+		return ""
+	}
+	return SecretMask
+}
+
+// Plain returns value of v as a string. Unlike String
+// value of Secret is revealed.
+func Plain(v Valuer) string {
+	if s, ok := v.(*Secret); ok {
+		return s.Reveal()
+	}
+	if s, ok := v.(interface{ String() string }); ok {
+		return s.String()
+	}
+	return ""
+}
@@ -0,0 +1,12 @@
+//go:build !js && !wasip1 && !plan9
+
+package gonfig
+
+import (
+	"os"
+	"syscall"
+)
+
+// defaultReloadSignals are used by ReloadOnSignal if signals are
+// not given.
+var defaultReloadSignals = []os.Signal{syscall.SIGHUP}
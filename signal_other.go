@@ -0,0 +1,9 @@
+//go:build js || wasip1 || plan9
+
+package gonfig
+
+import "os"
+
+// defaultReloadSignals are used by ReloadOnSignal if signals are
+// not given. There is no SIGHUP on the platform.
+var defaultReloadSignals []os.Signal
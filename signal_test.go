@@ -0,0 +1,62 @@
+//go:build unix
+
+package gonfig_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/axkit/gonfig"
+	"github.com/axkit/gonfig/gonfigfile"
+)
+
+func TestLoader_ReloadOnSignal(t *testing.T) {
+
+	path := filepath.Join(t.TempDir(), "app.yaml")
+	if err := os.WriteFile(path, []byte("port: 8080\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	reloaded := make(chan string, 2)
+	l := gonfig.NewLoader().
+		Add("file", gonfigfile.NewFileSource(path, "")).
+		WithLogf(func(format string, args ...interface{}) {
+			reloaded <- fmt.Sprintf(format, args...)
+		})
+
+	cfg := gonfig.New()
+	if err := l.Load(cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	var port gonfig.Int
+	cfg.BindVar("port", &port)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	l.ReloadOnSignal(ctx, cfg)
+
+	os.WriteFile(path, []byte("port: 9090\n"), 0600)
+
+	p, _ := os.FindProcess(os.Getpid())
+	if err := p.Signal(syscall.SIGHUP); err != nil {
+		t.Skip(err)
+	}
+
+	for i := 0; i < 2; i++ {
+		select {
+		case <-reloaded:
+		case <-time.After(2 * time.Second):
+			t.Fatal("config is not reloaded")
+		}
+	}
+
+	if port.Val() != 9090 {
+		t.Errorf("expected 9090, got %d", port.Val())
+	}
+}
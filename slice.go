@@ -0,0 +1,42 @@
+package gonfig
+
+import (
+	"strings"
+	"sync/atomic"
+)
+
+// DefaultSliceSeparator is used by StringSlice and IntSlice to split
+// input if separator was not specified explicitly.
+var DefaultSliceSeparator = ","
+
+// sliceRef is a memory shared by all binded slice valuers.
+// Whole slice is replaced atomically.
+type sliceRef struct {
+	val atomic.Value
+	sep atomic.Value
+}
+
+func (r *sliceRef) separator() string {
+	if s, ok := r.sep.Load().(string); ok && s != "" {
+		return s
+	}
+	return DefaultSliceSeparator
+}
+
+// splitSlice splits s by sep trimming spaces around every item.
+// Empty items are skipped.
+func splitSlice(s, sep string) []string {
+	var res []string
+	for _, item := range strings.Split(s, sep) {
+		item = strings.TrimSpace(item)
+		if item != "" {
+			res = append(res, item)
+		}
+	}
+	return res
+}
+
+// isJSONArray returns true if s looks like JSON array.
+func isJSONArray(s string) bool {
+	return strings.HasPrefix(s, "[") && strings.HasSuffix(s, "]")
+}
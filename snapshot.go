@@ -0,0 +1,123 @@
+package gonfig
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+type snapshotParam struct {
+	Code     string `json:"code"`
+	Kind     string `json:"kind"`
+	Value    string `json:"value"`
+	Template string `json:"template,omitempty"`
+}
+
+// MarshalJSON implements Marshaller interface. Values of secrets
+// are masked.
+func (s *Snapshot) MarshalJSON() ([]byte, error) {
+	res := make([]snapshotParam, len(s.params))
+	for i, st := range s.params {
+		sp := snapshotParam{Code: st.code, Kind: st.v.Kind().String()}
+		switch st.v.Kind() {
+		case ASecret:
+			sp.Value = mask(Plain(st.v))
+		case AString:
+			sp.Value = Plain(st.v)
+			if st.tmpl != escape(sp.Value) {
+				sp.Template = st.tmpl
+			}
+		default:
+			v, err := exact(st.v)
+			if err != nil {
+				return nil, err
+			}
+			sp.Value = v
+		}
+		res[i] = sp
+	}
+	return json.Marshal(res)
+}
+
+// UnmarshalJSON implements Unmarshaller interface. Secrets are
+// skipped, so Restore of unmarshaled snapshot keeps current values
+// of secrets.
+func (s *Snapshot) UnmarshalJSON(buf []byte) error {
+	var sps []snapshotParam
+	if err := json.Unmarshal(buf, &sps); err != nil {
+		return err
+	}
+
+	n := Snapshot{idx: make(map[string]int, len(sps))}
+	for _, sp := range sps {
+		ak, ok := kindByName(sp.Kind)
+		if !ok {
+			return fmt.Errorf("%w: %s of param '%s'", ErrUnknownKind, sp.Kind, sp.Code)
+		}
+		if ak == ASecret {
+			continue
+		}
+
+		v := makeValuer(ak)
+		if err := v.Parse(sp.Value); err != nil {
+			return fmt.Errorf("param '%s': %w", sp.Code, err)
+		}
+
+		st := staged{code: sp.Code, v: v}
+		if ak == AString {
+			st.tmpl = sp.Template
+			if st.tmpl == "" {
+				st.tmpl = escape(sp.Value)
+			}
+		}
+
+		if i, ok := n.idx[sp.Code]; ok {
+			n.params[i] = st
+			continue
+		}
+		n.idx[sp.Code] = len(n.params)
+		n.params = append(n.params, st)
+	}
+
+	*s = n
+	return nil
+}
+
+// exact returns value as a string parsed back to the same value.
+func exact(v Valuer) (string, error) {
+	switch x := v.(type) {
+	case *Float:
+		return formatFloat(x.Val()), nil
+	case *StringSlice:
+		return jsonStrings(x.Val()), nil
+	case *IntSlice:
+		return jsonInts(x.Val()), nil
+	}
+	return Plain(v), nil
+}
+
+// Restore assigns values from snapshot s taken by Snapshot.
+// Values are validated and published together as by Update.
+// Nothing is published if any param of snapshot is not in container,
+// has different kind or its value violates validation rules.
+// References of interpolated values are restored as well.
+func (c *Config) Restore(s *Snapshot) error {
+	return c.update(&author{source: "restore"}, func(x Tx) error {
+		t := x.(*tx)
+		for _, st := range s.params {
+			if ak := st.v.Kind(); ak == AString || ak == ASecret {
+				p, ok := t.c.Get(st.code)
+				if ok && p.Kind() != ak {
+					return fmt.Errorf("%w: param '%s' is %s, got %s", ErrDifferentKind, st.code, p.Kind(), ak)
+				}
+				if err := t.Parse(st.code, st.tmpl); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := t.set(st.code, st.v); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,177 @@
+package gonfig_test
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/axkit/gonfig"
+)
+
+type restoreConfig struct {
+	Host    gonfig.String      `cfg:"host" default:"db1"`
+	URL     gonfig.String      `cfg:"url" default:"pg://${host}/app"`
+	Port    gonfig.Int         `cfg:"port" default:"5432" max:"9999"`
+	Ratio   gonfig.Float       `cfg:"ratio" default:"0.1234567891"`
+	Timeout gonfig.Duration    `cfg:"timeout" default:"1m30s"`
+	Hosts   gonfig.StringSlice `cfg:"hosts" default:"a;b" sep:";"`
+	Pass    gonfig.Secret      `cfg:"pass" default:"qwerty"`
+}
+
+func TestConfig_Restore(t *testing.T) {
+
+	var c restoreConfig
+	cfg := gonfig.New().WithInterpolation()
+	if errs := cfg.BindStruct(&c); len(errs) > 0 {
+		t.Fatal(errs)
+	}
+
+	snap := cfg.Snapshot()
+
+	cfg.MustParam("url", gonfig.AString).Parse("static")
+	c.Port.Set(1)
+	c.Ratio.Set(2)
+	c.Timeout.Set(time.Second)
+	c.Hosts.Set([]string{"x"})
+	c.Pass.Set("changed")
+
+	if err := cfg.Restore(snap); err != nil {
+		t.Fatal(err)
+	}
+
+	if c.URL.Val() != "pg://db1/app" || c.Port.Val() != 5432 || c.Ratio.Val() != 0.1234567891 ||
+		c.Timeout.Val() != 90*time.Second || len(c.Hosts.Val()) != 2 || c.Pass.Reveal() != "qwerty" {
+		t.Errorf("unexpected values after restore: %s %d %v %v %v", c.URL.Val(), c.Port.Val(),
+			c.Ratio.Val(), c.Timeout.Val(), c.Hosts.Val())
+	}
+
+	// reference is restored.
+	c.Host.Set("db2")
+	if c.URL.Val() != "pg://db2/app" {
+		t.Errorf("expected url to follow host, got %s", c.URL.Val())
+	}
+}
+
+func TestSnapshot_JSON(t *testing.T) {
+
+	var c restoreConfig
+	cfg := gonfig.New().WithInterpolation()
+	cfg.BindStruct(&c)
+
+	buf, err := json.Marshal(cfg.Snapshot())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !json.Valid(buf) || strings.Contains(string(buf), "qwerty") {
+		t.Fatalf("unexpected snapshot %s", buf)
+	}
+
+	var snap gonfig.Snapshot
+	if err := json.Unmarshal(buf, &snap); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := snap.Get("pass"); ok {
+		t.Error("secret is expected to be skipped")
+	}
+
+	c.Ratio.Set(5)
+	c.Pass.Set("changed")
+	c.Host.Set("db3")
+	if err := cfg.Restore(&snap); err != nil {
+		t.Fatal(err)
+	}
+	if c.Ratio.Val() != 0.1234567891 || c.Pass.Reveal() != "changed" || c.URL.Val() != "pg://db1/app" {
+		t.Errorf("unexpected values %v %s %s", c.Ratio.Val(), c.Pass.Reveal(), c.URL.Val())
+	}
+}
+
+func TestConfig_RestoreFails(t *testing.T) {
+
+	var c restoreConfig
+	cfg := gonfig.New().WithInterpolation()
+	cfg.BindStruct(&c)
+
+	other := gonfig.New()
+	other.MustParam("host", gonfig.AString).Parse("db9")
+	other.MustParam("port", gonfig.AInt).Parse("10000")
+
+	if err := cfg.Restore(other.Snapshot()); err == nil {
+		t.Error("expected validation error")
+	}
+	if c.Host.Val() != "db1" {
+		t.Errorf("nothing expected to be published, got host %s", c.Host.Val())
+	}
+
+	other.MustParam("unknown", gonfig.AInt)
+	if err := cfg.Restore(other.Snapshot("unknown")); !errors.Is(err, gonfig.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+
+	other.MustParam("timeout", gonfig.AInt)
+	if err := cfg.Restore(other.Snapshot("timeout")); !errors.Is(err, gonfig.ErrDifferentKind) {
+		t.Errorf("expected ErrDifferentKind, got %v", err)
+	}
+}
+
+func TestConfig_Rollback(t *testing.T) {
+
+	var c restoreConfig
+	cfg := gonfig.New().WithHistory(3)
+	if err := cfg.Rollback(1); !errors.Is(err, gonfig.ErrNoHistory) {
+		t.Errorf("expected ErrNoHistory, got %v", err)
+	}
+
+	// assigned defaults are recorded as well.
+	cfg.BindStruct(&c)
+
+	c.Port.Set(1)
+	c.Port.Set(2)
+	c.Ratio.Set(0.5)
+	c.Pass.Set("x")
+
+	h := cfg.History()
+	if len(h) != 3 {
+		t.Fatalf("expected 3 changes, got %v", h)
+	}
+	if h[0].Code != "port" || h[0].Old != "1" || h[0].New != "2" || h[2].New != gonfig.SecretMask {
+		t.Errorf("unexpected history %v", h)
+	}
+
+	if err := cfg.Rollback(2); err != nil {
+		t.Fatal(err)
+	}
+	if c.Port.Val() != 2 || c.Ratio.Val() != 0.1234567891 || c.Pass.Reveal() != "qwerty" {
+		t.Errorf("unexpected values %d %v %s", c.Port.Val(), c.Ratio.Val(), c.Pass.Reveal())
+	}
+	if h := cfg.History(); len(h) != 1 {
+		t.Errorf("expected rolled back changes removed, got %v", h)
+	}
+
+	if err := cfg.Rollback(1); err != nil {
+		t.Fatal(err)
+	}
+	if c.Port.Val() != 1 || len(cfg.History()) != 0 {
+		t.Errorf("unexpected port %d, history %v", c.Port.Val(), cfg.History())
+	}
+
+	// params created later are recorded.
+	cfg.MustParam("later", gonfig.AInt).Parse("7")
+	if h := cfg.History(); len(h) != 1 || h[0].Code != "later" {
+		t.Errorf("unexpected history %v", h)
+	}
+
+	// items containing separator are restored as they were.
+	c.Hosts.Set([]string{"x;y"})
+	c.Hosts.Set([]string{"z"})
+	if h := cfg.History(); h[len(h)-1].Old != `["x;y"]` {
+		t.Errorf("unexpected history %v", h)
+	}
+	if err := cfg.Rollback(1); err != nil {
+		t.Fatal(err)
+	}
+	if hosts := c.Hosts.Val(); len(hosts) != 1 || hosts[0] != "x;y" {
+		t.Errorf("unexpected hosts %q", hosts)
+	}
+}
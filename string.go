@@ -12,6 +12,9 @@ var NonBindedString string = ""
 // String implements atomic string.
 type String struct {
 	ref *string
+
+	// c is container the param belongs to.
+	c *Config
 }
 
 // NewString returns atomic string implemented as atomic ptr.
@@ -26,9 +29,31 @@ func (a *String) Kind() AKind {
 
 // Set assigns value atomically. Initializes if was not before.
 func (a *String) Set(s string) {
+	if a.owner().rejected(a.mem(), func() Valuer {
+		n := NewString()
+		n.Set(s)
+		return n
+	}) {
+		return
+	}
+	a.set(s, nil)
+}
+
+// set is like Set, the change is attributed to by.
+func (a *String) set(s string, by *author) {
 	sp := new(string)
 	*sp = s
 	ptr := atomic.LoadPointer((*unsafe.Pointer)(unsafe.Pointer(&a.ref)))
+	if n := a.owner().notifierOf(ptr); n != nil {
+		n.change(by, func() (string, string, bool) {
+			old := NonBindedString
+			if op := atomic.SwapPointer((*unsafe.Pointer)(ptr), (unsafe.Pointer)(sp)); op != nil {
+				old = *(*string)(op)
+			}
+			return old, s, old != s
+		})
+		return
+	}
 	if ptr != nil {
 		atomic.StorePointer((*unsafe.Pointer)(ptr), (unsafe.Pointer)(sp))
 		return
@@ -59,11 +84,28 @@ func (a *String) IsBinded() bool {
 	return atomic.LoadPointer((*unsafe.Pointer)(unsafe.Pointer(&a.ref))) != nil
 }
 
+func (a *String) mem() unsafe.Pointer {
+	return atomic.LoadPointer((*unsafe.Pointer)(unsafe.Pointer(&a.ref)))
+}
+
+func (a *String) owner() *Config {
+	return loadOwner(&a.c)
+}
+
+func (a *String) own(c *Config) {
+	storeOwner(&a.c, c)
+}
+
+func (a *String) assign(v Valuer, by *author) {
+	a.set(v.(*String).Val(), by)
+}
+
 // Bind binds current atomic variable to variable identified by to.
 // As a result two String address to the same variable.
 func (a *String) Bind(i *String) {
 	ptr := atomic.LoadPointer((*unsafe.Pointer)(unsafe.Pointer(&i.ref)))
 	if ptr != nil {
+		storeOwner(&a.c, i.owner())
 		atomic.StorePointer((*unsafe.Pointer)(unsafe.Pointer(&a.ref)), ptr)
 	}
 }
@@ -90,7 +132,34 @@ func (a *String) UnmarshalJSON(buf []byte) error {
 }
 
 // Parse implements Valuer interface. Calls Set.
+// Value is not changed if it violates validation rules.
+//
+// If interpolation is turned on by Config.WithInterpolation, values
+// of params created by Config may reference other params as ${code}
+// and environment variables as ${env:NAME}. The value is re-evaluated
+// when referenced param changes. $${ is replaced by ${. Otherwise
+// the value is kept as is.
 func (a *String) Parse(s string) error {
+	e := a.owner().expanderOf(a.mem())
+	tmpl := s
+	if e != nil {
+		var err error
+		if s, err = e.expand(s); err != nil {
+			return err
+		}
+	}
+
+	if vr := a.owner().validatorOf(a.mem()); vr != nil {
+		n := NewString()
+		n.Set(s)
+		if err := vr.check(n); err != nil {
+			return err
+		}
+	}
 	a.Set(s)
+
+	if e != nil {
+		e.commit(tmpl)
+	}
 	return nil
 }
@@ -0,0 +1,203 @@
+package gonfig
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"unsafe"
+)
+
+// NonBindedStringSlice is returned by method Val
+// if StringSlice is not initialized yet.
+var NonBindedStringSlice []string
+
+// StringSlice implements atomic slice of strings.
+type StringSlice struct {
+	ref *sliceRef
+
+	// c is container the param belongs to.
+	c *Config
+}
+
+// NewStringSlice returns atomic slice of strings.
+func NewStringSlice() *StringSlice {
+	return &StringSlice{ref: new(sliceRef)}
+}
+
+// Kind returns AStringSlice.
+func (a *StringSlice) Kind() AKind {
+	return AStringSlice
+}
+
+func (a *StringSlice) load() *sliceRef {
+	return (*sliceRef)(atomic.LoadPointer((*unsafe.Pointer)(unsafe.Pointer(&a.ref))))
+}
+
+// Set assigns a copy of ss atomically. Initializes if was not before.
+func (a *StringSlice) Set(ss []string) {
+	if a.owner().rejected(a.mem(), func() Valuer {
+		n := NewStringSlice()
+		n.Set(ss)
+		return n
+	}) {
+		return
+	}
+	a.set(ss, nil)
+}
+
+// set is like Set, the change is attributed to by.
+func (a *StringSlice) set(ss []string, by *author) {
+	cp := make([]string, len(ss))
+	copy(cp, ss)
+
+	ref := a.load()
+	if n := a.owner().notifierOf(unsafe.Pointer(ref)); n != nil {
+		n.change(by, func() (string, string, bool) {
+			old, _ := ref.val.Swap(cp).([]string)
+			return jsonStrings(old), jsonStrings(cp), !slices.Equal(old, cp)
+		})
+		return
+	}
+	if ref == nil {
+		n := NewStringSlice()
+		a.Bind(n)
+		ref = n.ref
+	}
+	ref.val.Store(cp)
+}
+
+// Val returns a copy of value. Returns NonBindedStringSlice
+// if it's not binded to params container.
+func (a *StringSlice) Val() []string {
+	ref := a.load()
+	if ref == nil {
+		return NonBindedStringSlice
+	}
+	ss, _ := ref.val.Load().([]string)
+	res := make([]string, len(ss))
+	copy(res, ss)
+	return res
+}
+
+// Len returns number of items without copying the slice.
+func (a *StringSlice) Len() int {
+	ref := a.load()
+	if ref == nil {
+		return 0
+	}
+	ss, _ := ref.val.Load().([]string)
+	return len(ss)
+}
+
+// SetSeparator sets separator used by Parse and String.
+// Separator is shared by all binded variables.
+func (a *StringSlice) SetSeparator(sep string) {
+	ref := a.load()
+	if ref == nil {
+		n := NewStringSlice()
+		a.Bind(n)
+		ref = n.ref
+	}
+	ref.sep.Store(sep)
+}
+
+// IsBinded returns true if StringSlice bineded to params container.
+func (a *StringSlice) IsBinded() bool {
+	return a.load() != nil
+}
+
+func (a *StringSlice) mem() unsafe.Pointer {
+	return unsafe.Pointer(a.load())
+}
+
+func (a *StringSlice) owner() *Config {
+	return loadOwner(&a.c)
+}
+
+func (a *StringSlice) own(c *Config) {
+	storeOwner(&a.c, c)
+}
+
+func (a *StringSlice) assign(v Valuer, by *author) {
+	a.set(v.(*StringSlice).Val(), by)
+}
+
+// Bind binds current atomic variable to variable identified by to.
+// As a result two StringSlice address to the same variable.
+func (a *StringSlice) Bind(to *StringSlice) {
+	ptr := atomic.LoadPointer((*unsafe.Pointer)(unsafe.Pointer(&to.ref)))
+	if ptr != nil {
+		storeOwner(&a.c, to.owner())
+		atomic.StorePointer((*unsafe.Pointer)(unsafe.Pointer(&a.ref)), ptr)
+	}
+}
+
+// String implements Stringer interface. Items are joined by separator.
+func (a *StringSlice) String() string {
+	ref := a.load()
+	if ref == nil {
+		return ""
+	}
+	ss, _ := ref.val.Load().([]string)
+	return strings.Join(ss, ref.separator())
+}
+
+// Parse converts input argument and assigns to value. Accepts
+// items delimited by separator or JSON array of strings.
+// Value is not changed if it violates validation rules.
+func (a *StringSlice) Parse(s string) error {
+	s = strings.TrimSpace(s)
+
+	var ss []string
+	if isJSONArray(s) {
+		if err := json.Unmarshal([]byte(s), &ss); err != nil {
+			return err
+		}
+	} else {
+		sep := DefaultSliceSeparator
+		if ref := a.load(); ref != nil {
+			sep = ref.separator()
+		}
+		ss = splitSlice(s, sep)
+	}
+
+	if vr := a.owner().validatorOf(a.mem()); vr != nil {
+		n := NewStringSlice()
+		n.Set(ss)
+		if err := vr.check(n); err != nil {
+			return err
+		}
+	}
+	a.Set(ss)
+	return nil
+}
+
+// jsonStrings returns ss as JSON array, which is parsed back
+// to the same items regardless of separator.
+func jsonStrings(ss []string) string {
+	if ss == nil {
+		ss = []string{}
+	}
+	buf, _ := json.Marshal(ss)
+	return string(buf)
+}
+
+// MarshalJSON implement Marshaller interface.
+func (a StringSlice) MarshalJSON() ([]byte, error) {
+	ss := a.Val()
+	if ss == nil {
+		ss = []string{}
+	}
+	return json.Marshal(ss)
+}
+
+// UnmarshalJSON implement Unmarshaller interface.
+func (a *StringSlice) UnmarshalJSON(buf []byte) error {
+	var ss []string
+	if err := json.Unmarshal(buf, &ss); err != nil {
+		return err
+	}
+	a.Set(ss)
+	return nil
+}
@@ -0,0 +1,205 @@
+package gonfig
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// Sub returns view of params having codes starting with prefix
+// followed by separator set by WithPrefixSeparator. Codes passed
+// to and returned by the view are relative to the prefix.
+// The view shares params with the container, so changes made by
+// any of them are seen by all.
+//
+// It's intended for libraries receiving only their part of config:
+//
+//	db.Open(cfg.Sub("db")) // binds db.host, db.port
+//
+// Besides Configer the view implements Originer, Updater,
+// Snapshotter, Describer and FieldBinder, methods Restore, Export,
+// Audit and Sub. History, Rollback, Freeze and the With methods
+// apply to the whole container.
+func (c *Config) Sub(prefix string) Configer {
+	c.mux.RLock()
+	defer c.mux.RUnlock()
+	return &sub{Config: c, prefix: prefix + c.prefixSep()}
+}
+
+// sub is a view of params having codes with prefix.
+type sub struct {
+	*Config
+	prefix string
+}
+
+func (s *sub) Sub(prefix string) Configer {
+	return s.Config.Sub(s.prefix + prefix)
+}
+
+func (s *sub) Param(code string, ak AKind) (Valuer, error) {
+	return s.Config.Param(s.prefix+code, ak)
+}
+
+func (s *sub) MustParam(code string, ak AKind) Valuer {
+	return s.Config.MustParam(s.prefix+code, ak)
+}
+
+func (s *sub) IsExist(code string) bool {
+	return s.Config.IsExist(s.prefix + code)
+}
+
+func (s *sub) Get(code string) (Valuer, bool) {
+	return s.Config.Get(s.prefix + code)
+}
+
+func (s *sub) BindStruct(structAddr interface{}) []error {
+	s.mux.Lock()
+	defer s.unlock()
+	return s.bindStruct(s.prefix, structAddr)
+}
+
+func (s *sub) BindVar(code string, v Valuer) error {
+	return s.Config.BindVar(s.prefix+code, v)
+}
+
+func (s *sub) BindField(code string, v Valuer, tag reflect.StructTag) []error {
+	return s.Config.BindField(s.prefix+code, v, tag)
+}
+
+func (s *sub) Walk(f func(code string, v Valuer, inited, asked int)) {
+	s.Config.Walk(func(code string, v Valuer, inited, asked int) {
+		if rel, ok := strings.CutPrefix(code, s.prefix); ok {
+			f(rel, v, inited, asked)
+		}
+	})
+}
+
+func (s *sub) Origin(code string) (Origin, bool) {
+	return s.Config.Origin(s.prefix + code)
+}
+
+func (s *sub) SetOrigin(code string, o Origin) {
+	s.Config.SetOrigin(s.prefix+code, o)
+}
+
+func (s *sub) OnChange(code string, f func(old, new string)) (func(), error) {
+	return s.Config.OnChange(s.prefix+code, f)
+}
+
+func (s *sub) Watch(code string) (<-chan Change, func(), error) {
+	return s.Config.Watch(s.prefix + code)
+}
+
+func (s *sub) Update(f func(tx Tx) error) error {
+	return s.UpdateContext(context.Background(), f)
+}
+
+func (s *sub) UpdateContext(ctx context.Context, f func(tx Tx) error) error {
+	return s.Config.UpdateContext(ctx, func(tx Tx) error {
+		return f(&subTx{Tx: tx, prefix: s.prefix})
+	})
+}
+
+func (s *sub) ParseContext(ctx context.Context, code, v string) error {
+	return s.Config.ParseContext(ctx, s.prefix+code, v)
+}
+
+// Snapshot returns snapshot of params of the view. Codes of
+// snapshot are relative to the prefix.
+func (s *sub) Snapshot(codes ...string) *Snapshot {
+	if len(codes) == 0 {
+		s.Walk(func(code string, v Valuer, inited, asked int) {
+			codes = append(codes, code)
+		})
+		if len(codes) == 0 {
+			return &Snapshot{idx: make(map[string]int)}
+		}
+	}
+
+	full := make([]string, len(codes))
+	for i := range codes {
+		full[i] = s.prefix + codes[i]
+	}
+	return s.Config.Snapshot(full...).renamed(func(code string) string {
+		return strings.TrimPrefix(code, s.prefix)
+	})
+}
+
+func (s *sub) Restore(snap *Snapshot) error {
+	return s.Config.Restore(snap.renamed(func(code string) string {
+		return s.prefix + code
+	}))
+}
+
+func (s *sub) Export(w io.Writer, f ExportFormat) error {
+	return (&Exporter{Format: f}).Export(w, s)
+}
+
+func (s *sub) Info(code string) (ParamInfo, bool) {
+	info, ok := s.Config.Info(s.prefix + code)
+	info.Code = strings.TrimPrefix(info.Code, s.prefix)
+	return info, ok
+}
+
+func (s *sub) WalkInfo(f func(v Valuer, info ParamInfo)) {
+	s.Config.WalkInfo(func(v Valuer, info ParamInfo) {
+		if rel, ok := strings.CutPrefix(info.Code, s.prefix); ok {
+			info.Code = rel
+			f(v, info)
+		}
+	})
+}
+
+// Audit returns report of params of the view.
+func (s *sub) Audit() AuditReport {
+	all := s.Config.Audit()
+	var res AuditReport
+	for _, p := range all.Unbound {
+		if rel, ok := strings.CutPrefix(p.Code, s.prefix); ok {
+			p.Code = rel
+			p.Similar = strings.TrimPrefix(p.Similar, s.prefix)
+			res.Unbound = append(res.Unbound, p)
+		}
+	}
+	for _, code := range all.Defaulted {
+		if rel, ok := strings.CutPrefix(code, s.prefix); ok {
+			res.Defaulted = append(res.Defaulted, rel)
+		}
+	}
+	return res
+}
+
+// subTx prefixes codes of params changed by transaction.
+type subTx struct {
+	Tx
+	prefix string
+}
+
+func (t *subTx) Parse(code, s string) error {
+	return t.Tx.Parse(t.prefix+code, s)
+}
+
+func (t *subTx) Get(code string) (Valuer, bool) {
+	return t.Tx.Get(t.prefix + code)
+}
+
+func (t *subTx) Create(code string, ak AKind, s string) error {
+	tc, ok := t.Tx.(TxCreator)
+	if !ok {
+		return fmt.Errorf("param '%s%s': transaction can't create params", t.prefix, code)
+	}
+	return tc.Create(t.prefix+code, ak, s)
+}
+
+// renamed returns copy of snapshot having codes changed by f.
+func (s *Snapshot) renamed(f func(code string) string) *Snapshot {
+	res := &Snapshot{params: make([]staged, len(s.params)), idx: make(map[string]int, len(s.params))}
+	for i, st := range s.params {
+		st.code = f(st.code)
+		res.params[i] = st
+		res.idx[st.code] = i
+	}
+	return res
+}
@@ -0,0 +1,104 @@
+package gonfig_test
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/axkit/gonfig"
+)
+
+// view lists methods of views returned by Sub besides Configer.
+type view interface {
+	gonfig.Configer
+	gonfig.Updater
+	gonfig.Snapshotter
+	gonfig.Describer
+	Restore(s *gonfig.Snapshot) error
+	Export(w io.Writer, f gonfig.ExportFormat) error
+	Audit() gonfig.AuditReport
+	Sub(prefix string) gonfig.Configer
+}
+
+type subDB struct {
+	Host gonfig.String `cfg:"host" default:"localhost"`
+	Port gonfig.Int    `cfg:"port" default:"5432"`
+}
+
+func TestConfig_Sub(t *testing.T) {
+
+	cfg := gonfig.New()
+	cfg.MustParam("db.port", gonfig.AInt).Parse("6432")
+	cfg.MustParam("http.port", gonfig.AInt).Parse("80")
+
+	db := cfg.Sub("db").(view)
+	var c subDB
+	if errs := db.BindStruct(&c); len(errs) > 0 {
+		t.Fatal(errs)
+	}
+	if c.Host.Val() != "localhost" || c.Port.Val() != 6432 {
+		t.Errorf("unexpected values %s %d", c.Host.Val(), c.Port.Val())
+	}
+	if !cfg.IsExist("db.host") || !db.IsExist("host") || db.IsExist("http.port") {
+		t.Error("unexpected params")
+	}
+
+	// the view shares memory with the container.
+	cfg.MustParam("db.port", gonfig.AInt).Parse("7432")
+	if v, ok := db.Get("port"); !ok || v.(*gonfig.Int).Val() != 7432 || c.Port.Val() != 7432 {
+		t.Errorf("expected 7432, got %d", c.Port.Val())
+	}
+	if err := db.MustParam("port", gonfig.AInt).Parse("8432"); err != nil || c.Port.Val() != 8432 {
+		t.Errorf("expected 8432, got %d %v", c.Port.Val(), err)
+	}
+
+	var codes []string
+	db.Walk(func(code string, v gonfig.Valuer, inited, asked int) {
+		codes = append(codes, code)
+	})
+	if strings.Join(codes, ",") != "port,host" {
+		t.Errorf("unexpected codes %v", codes)
+	}
+
+	if err := db.Update(func(tx gonfig.Tx) error {
+		return tx.Parse("host", "db.local")
+	}); err != nil || c.Host.Val() != "db.local" {
+		t.Errorf("expected db.local, got %s %v", c.Host.Val(), err)
+	}
+
+	s := db.Snapshot()
+	if v, ok := s.Get("host"); !ok || v.(*gonfig.String).Val() != "db.local" {
+		t.Error("expected host in snapshot")
+	}
+	c.Host.Parse("other")
+	if err := db.Restore(s); err != nil || c.Host.Val() != "db.local" {
+		t.Errorf("expected restored value, got %s %v", c.Host.Val(), err)
+	}
+
+	if info, ok := db.Info("port"); !ok || info.Code != "port" || info.Default != "5432" {
+		t.Errorf("unexpected info %+v", info)
+	}
+
+	var buf bytes.Buffer
+	if err := db.Export(&buf, gonfig.ExportJSONFlat); err != nil || strings.Contains(buf.String(), "http") || !strings.Contains(buf.String(), `"port"`) {
+		t.Errorf("unexpected export %s %v", buf.String(), err)
+	}
+
+	// nested views join prefixes.
+	db.Sub("pool").MustParam("size", gonfig.AInt)
+	if !cfg.IsExist("db.pool.size") || !db.IsExist("pool.size") {
+		t.Error("expected nested view")
+	}
+}
+
+func TestConfig_Sub_Separator(t *testing.T) {
+	cfg := gonfig.New().WithPrefixSeparator("_")
+	var c subDB
+	if errs := cfg.Sub("db").BindStruct(&c); len(errs) > 0 {
+		t.Fatal(errs)
+	}
+	if !cfg.IsExist("db_port") {
+		t.Error("expected db_port")
+	}
+}
@@ -0,0 +1,384 @@
+package gonfig
+
+import (
+	"context"
+	"fmt"
+)
+
+// assigner is implemented by all valuers. assign copies value
+// from v having the same kind.
+type assigner interface {
+	assign(v Valuer, by *author)
+}
+
+// clone returns a copy of v not binded to v.
+func clone(v Valuer) Valuer {
+	n := makeValuer(v.Kind())
+	switch x := v.(type) {
+	case *StringSlice:
+		if ref := x.load(); ref != nil {
+			n.(*StringSlice).SetSeparator(ref.separator())
+		}
+	case *IntSlice:
+		if ref := x.load(); ref != nil {
+			n.(*IntSlice).SetSeparator(ref.separator())
+		}
+	}
+	n.(assigner).assign(v, nil)
+	return n
+}
+
+// A Tx is an interface what wraps following methods:
+//
+// Parse parses and validates value of param identified by code.
+// Value is published when Update function returns nil.
+//
+// Get returns value of param identified by code, parsed in the
+// transaction or current one. Returned Valuer is not binded to
+// the container.
+type Tx interface {
+	Parse(code, s string) error
+	Get(code string) (Valuer, bool)
+}
+
+// A TxCreator is implemented by transactions of Update.
+//
+// Create stages value s of new param identified by code having
+// kind ak. The param is added to the container when Update function
+// returns nil, so nothing is added if transaction fails. Existing
+// param keeps own kind and parses s like Parse does.
+type TxCreator interface {
+	Create(code string, ak AKind, s string) error
+}
+
+type staged struct {
+	code string
+	p    Valuer
+	v    Valuer
+
+	// created is true if param p is added to container on commit.
+	created bool
+
+	// e and tmpl are used to commit template of interpolated value.
+	// Snapshot keeps template of AString and ASecret params in tmpl.
+	e    *expander
+	tmpl string
+}
+
+type tx struct {
+	c      *Config
+	staged []staged
+	idx    map[string]int
+}
+
+func (t *tx) Parse(code, s string) error {
+	if i, ok := t.idx[code]; ok {
+		st := &t.staged[i]
+		v := clone(st.v)
+		if err := t.parse(st, v, s); err != nil {
+			return err
+		}
+		st.v = v
+		return nil
+	}
+
+	p, ok := t.c.Get(code)
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrNotFound, code)
+	}
+
+	st := staged{code: code, p: p}
+	v := clone(p)
+	if err := t.parse(&st, v, s); err != nil {
+		return err
+	}
+	st.v = v
+
+	t.idx[code] = len(t.staged)
+	t.staged = append(t.staged, st)
+	return nil
+}
+
+func (t *tx) Create(code string, ak AKind, s string) error {
+	if _, ok := t.idx[code]; ok {
+		return t.Parse(code, s)
+	}
+	if _, ok := t.c.Get(code); ok {
+		return t.Parse(code, s)
+	}
+	if !ak.isValid() {
+		return fmt.Errorf("%w: param '%s'", ErrUnknownKind, code)
+	}
+
+	p := makeValuer(ak)
+	st := staged{code: code, p: p, created: true}
+	if t.c.interpolated() && (ak == AString || ak == ASecret) {
+		// expander is registered when param is added on commit.
+		st.e = &expander{c: t.c, code: code, v: p}
+	}
+
+	v := clone(p)
+	if err := t.parse(&st, v, s); err != nil {
+		return err
+	}
+	st.v = v
+
+	t.idx[code] = len(t.staged)
+	t.staged = append(t.staged, st)
+	return nil
+}
+
+// parse parses s into candidate v and checks it against
+// validation rules of param st.p. References are resolved
+// by expander of param.
+func (t *tx) parse(st *staged, v Valuer, s string) error {
+	w, ok := st.p.(watchable)
+	if !ok {
+		return v.Parse(s)
+	}
+
+	tmpl := s
+	e := st.e
+	if e == nil {
+		e = t.c.expanderOf(w.mem())
+	}
+	if e != nil {
+		var err error
+		if s, err = e.expandWith(s, t.lookup); err != nil {
+			return err
+		}
+		st.e, st.tmpl = e, tmpl
+	}
+
+	if err := v.Parse(s); err != nil {
+		return err
+	}
+	return t.c.validate(w.mem(), v)
+}
+
+// lookup returns staged or current value without copying.
+func (t *tx) lookup(code string) (Valuer, bool) {
+	if i, ok := t.idx[code]; ok {
+		return t.staged[i].v, true
+	}
+	return t.c.lookup(code)
+}
+
+// expand resolves templates of staged values again, so they
+// reference values staged later.
+func (t *tx) expand() error {
+	for i := range t.staged {
+		st := &t.staged[i]
+		if st.e == nil {
+			continue
+		}
+		v := clone(st.p)
+		if err := t.parse(st, v, st.tmpl); err != nil {
+			return err
+		}
+		st.v = v
+	}
+	return nil
+}
+
+// set stages copy of v as value of param identified by code.
+func (t *tx) set(code string, v Valuer) error {
+	var p Valuer
+	i, exists := t.idx[code]
+	if exists {
+		p = t.staged[i].p
+	} else {
+		var ok bool
+		if p, ok = t.c.Get(code); !ok {
+			return fmt.Errorf("%w: %s", ErrNotFound, code)
+		}
+	}
+
+	if p.Kind() != v.Kind() {
+		return fmt.Errorf("%w: param '%s' is %s, got %s", ErrDifferentKind, code, p.Kind(), v.Kind())
+	}
+
+	n := clone(v)
+	if err := t.c.validate(p.(watchable).mem(), n); err != nil {
+		return err
+	}
+
+	if exists {
+		t.staged[i].v = n
+		return nil
+	}
+	t.idx[code] = len(t.staged)
+	t.staged = append(t.staged, staged{code: code, p: p, v: n})
+	return nil
+}
+
+// create adds params staged by Create to container. Param added
+// meanwhile by other goroutine is used if it has the same kind.
+func (t *tx) create() error {
+	t.c.mux.Lock()
+	defer t.c.mux.Unlock()
+
+	for _, st := range t.staged {
+		if !st.created {
+			continue
+		}
+		if idx, ok := t.c.idx[st.code]; ok && t.c.list[idx].av.Kind() != st.v.Kind() {
+			return fmt.Errorf("%w: param '%s' is %s, got %s", ErrDifferentKind, st.code, t.c.list[idx].av.Kind(), st.v.Kind())
+		}
+	}
+
+	for i := range t.staged {
+		st := &t.staged[i]
+		if !st.created {
+			continue
+		}
+		if idx, ok := t.c.idx[st.code]; ok {
+			st.p = t.c.list[idx].av
+		} else {
+			t.c.add(st.code, st.p)
+		}
+		t.c.setStat(st.code, added)
+		if st.e != nil {
+			st.e = t.c.expanderOf(st.p.(watchable).mem())
+		}
+	}
+	return nil
+}
+
+func (t *tx) Get(code string) (Valuer, bool) {
+	if i, ok := t.idx[code]; ok {
+		return clone(t.staged[i].v), true
+	}
+	p, ok := t.c.Get(code)
+	if !ok {
+		return nil, false
+	}
+	return clone(p), true
+}
+
+// Update calls f with transaction. Values parsed in the transaction
+// are validated first and published together if f returns nil.
+// Nothing is published if f or any Parse returns error.
+//
+// Published values are observed consistently by Snapshot. Readers of
+// individual variables may observe the values published one by one.
+// OnChange callbacks are called after all values are published.
+func (c *Config) Update(f func(tx Tx) error) error {
+	return c.UpdateContext(context.Background(), f)
+}
+
+// UpdateContext is like Update, changes are attributed to actor and
+// source carried by ctx, see WithActor and WithSource.
+func (c *Config) UpdateContext(ctx context.Context, f func(tx Tx) error) error {
+	return c.update(authorOf(ctx), f)
+}
+
+func (c *Config) update(by *author, f func(tx Tx) error) error {
+	t := &tx{c: c, idx: make(map[string]int)}
+	if err := f(t); err != nil {
+		return err
+	}
+
+	if len(t.staged) == 0 {
+		return nil
+	}
+
+	if err := t.expand(); err != nil {
+		return err
+	}
+
+	if err := t.create(); err != nil {
+		return err
+	}
+
+	var held []*notifier
+	for _, st := range t.staged {
+		if n := c.notifierOf(st.p.(watchable).mem()); n != nil && n.hold() {
+			held = append(held, n)
+		}
+	}
+
+	c.txmux.Lock()
+	for _, st := range t.staged {
+		st.p.(assigner).assign(st.v, by)
+	}
+	c.txmux.Unlock()
+
+	if by != nil && by.source != "" && !by.rollback {
+		for _, st := range t.staged {
+			c.SetOrigin(st.code, Origin{Source: by.source})
+		}
+	}
+
+	for _, st := range t.staged {
+		if st.e != nil {
+			st.e.commit(st.tmpl)
+		}
+	}
+
+	for _, n := range held {
+		n.release()
+	}
+	return nil
+}
+
+// Snapshot is a consistent immutable copy of param values.
+type Snapshot struct {
+	params []staged
+	idx    map[string]int
+}
+
+// Get returns copy of param value identified by code.
+func (s *Snapshot) Get(code string) (Valuer, bool) {
+	i, ok := s.idx[code]
+	if !ok {
+		return nil, false
+	}
+	return clone(s.params[i].v), true
+}
+
+// Codes returns codes of params in snapshot.
+func (s *Snapshot) Codes() []string {
+	res := make([]string, len(s.params))
+	for i := range s.params {
+		res[i] = s.params[i].code
+	}
+	return res
+}
+
+// Snapshot returns consistent copy of params identified by codes
+// or of all params if codes are not given. Values published by
+// Update are either all in snapshot or none of them. Unknown codes
+// are skipped.
+func (c *Config) Snapshot(codes ...string) *Snapshot {
+	c.mux.RLock()
+	defer c.mux.RUnlock()
+
+	if len(codes) == 0 {
+		codes = make([]string, len(c.list))
+		for i := range c.list {
+			codes[i] = c.list[i].code
+		}
+	}
+
+	s := &Snapshot{idx: make(map[string]int, len(codes))}
+
+	c.txmux.RLock()
+	defer c.txmux.RUnlock()
+	for _, code := range codes {
+		idx, ok := c.idx[code]
+		if !ok {
+			continue
+		}
+		if _, ok := s.idx[code]; ok {
+			continue
+		}
+		s.idx[code] = len(s.params)
+		st := staged{code: code, v: clone(c.list[idx].av)}
+		if ak := st.v.Kind(); ak == AString || ak == ASecret {
+			st.tmpl = c.templateOf(c.list[idx].av)
+		}
+		s.params = append(s.params, st)
+	}
+	return s
+}
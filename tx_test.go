@@ -0,0 +1,157 @@
+package gonfig_test
+
+import (
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/axkit/gonfig"
+)
+
+func TestConfig_Update(t *testing.T) {
+
+	type DB struct {
+		Host gonfig.String `cfg:"db_host" default:"db1"`
+		Port gonfig.Int    `cfg:"db_port" default:"5432" min:"1"`
+	}
+
+	var db DB
+	cfg := gonfig.New()
+	cfg.BindStruct(&db)
+
+	var changes []string
+	cancel, _ := cfg.OnChange("db_port", func(old, new string) {
+		// all values are published before callbacks are called.
+		s := cfg.Snapshot("db_host")
+		h, _ := s.Get("db_host")
+		changes = append(changes, h.(*gonfig.String).Val()+":"+new)
+	})
+	defer cancel()
+
+	err := cfg.Update(func(tx gonfig.Tx) error {
+		if err := tx.Parse("db_host", "db2"); err != nil {
+			return err
+		}
+		return tx.Parse("db_port", "0")
+	})
+	var ve *gonfig.ValidationError
+	if !errors.As(err, &ve) {
+		t.Errorf("expected validation error, got %v", err)
+	}
+	if db.Host.Val() != "db1" || db.Port.Val() != 5432 {
+		t.Error("failed transaction must not publish values")
+	}
+
+	err = cfg.Update(func(tx gonfig.Tx) error {
+		if err := tx.Parse("db_host", "db2"); err != nil {
+			return err
+		}
+		if err := tx.Parse("db_port", "6432"); err != nil {
+			return err
+		}
+		if v, _ := tx.Get("db_port"); v.(*gonfig.Int).Val() != 6432 {
+			t.Error("Get must return staged value")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Error(err)
+	}
+
+	if db.Host.Val() != "db2" || db.Port.Val() != 6432 {
+		t.Error("values are not published")
+	}
+
+	if len(changes) != 1 || changes[0] != "db2:6432" {
+		t.Errorf("unexpected changes: %v", changes)
+	}
+
+	err = cfg.Update(func(tx gonfig.Tx) error {
+		return tx.Parse("unknown", "1")
+	})
+	if !errors.Is(err, gonfig.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestConfig_UpdateCreate(t *testing.T) {
+
+	cfg := gonfig.New()
+	cfg.MustParam("port", gonfig.AInt).Parse("80")
+
+	err := cfg.Update(func(tx gonfig.Tx) error {
+		tc := tx.(gonfig.TxCreator)
+		if err := tc.Create("host", gonfig.AString, "a"); err != nil {
+			return err
+		}
+		return tc.Create("port", gonfig.AInt, "x")
+	})
+	if err == nil {
+		t.Error("expected error")
+	}
+	if cfg.IsExist("host") {
+		t.Error("failed transaction must not create params")
+	}
+
+	err = cfg.Update(func(tx gonfig.Tx) error {
+		tc := tx.(gonfig.TxCreator)
+		if err := tc.Create("host", gonfig.AString, "a"); err != nil {
+			return err
+		}
+		if v, ok := tx.Get("host"); !ok || gonfig.Plain(v) != "a" {
+			t.Error("Get must return staged value")
+		}
+		return tc.Create("port", gonfig.AString, "81")
+	})
+	if err != nil {
+		t.Error(err)
+	}
+	if v, ok := cfg.Get("host"); !ok || gonfig.Plain(v) != "a" {
+		t.Error("param is not created")
+	}
+	if v, _ := cfg.Get("port"); v.Kind() != gonfig.AInt || gonfig.Plain(v) != "81" {
+		t.Error("existing param must keep own kind")
+	}
+}
+
+func TestConfig_SnapshotConsistent(t *testing.T) {
+
+	cfg := gonfig.New()
+	cfg.MustParam("a", gonfig.AInt).Parse("0")
+	cfg.MustParam("b", gonfig.AInt).Parse("0")
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 1; i <= 500; i++ {
+			s := strconv.Itoa(i)
+			cfg.Update(func(tx gonfig.Tx) error {
+				tx.Parse("a", s)
+				return tx.Parse("b", s)
+			})
+		}
+	}()
+
+	for i := 0; i < 500; i++ {
+		s := cfg.Snapshot()
+		a, _ := s.Get("a")
+		b, _ := s.Get("b")
+		if a.(*gonfig.Int).Val() != b.(*gonfig.Int).Val() {
+			t.Fatalf("inconsistent snapshot: a=%d, b=%d", a.(*gonfig.Int).Val(), b.(*gonfig.Int).Val())
+		}
+	}
+	wg.Wait()
+
+	s := cfg.Snapshot("b", "unknown")
+	if codes := s.Codes(); len(codes) != 1 || codes[0] != "b" {
+		t.Errorf("unexpected codes: %v", codes)
+	}
+
+	v, _ := s.Get("b")
+	v.(*gonfig.Int).Set(-1)
+	if p, _ := cfg.Get("b"); p.(*gonfig.Int).Val() != 500 {
+		t.Error("snapshot must be detached from container")
+	}
+}
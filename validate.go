@@ -0,0 +1,279 @@
+package gonfig
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unsafe"
+)
+
+// validationTags lists struct tags holding validation rules.
+var validationTags = []string{"required", "min", "max", "oneof", "pattern"}
+
+// ValidationError raises when value violates validation rule.
+type ValidationError struct {
+	Code  string
+	Rule  string
+	Value string
+}
+
+// Error implements error interface.
+func (e *ValidationError) Error() string {
+	switch e.Rule {
+	case "required":
+		return fmt.Sprintf("param '%s' is required", e.Code)
+	case "static":
+		return fmt.Sprintf("param '%s': %s", e.Code, ErrStatic)
+	}
+	return fmt.Sprintf("param '%s': value '%s' violates rule %s", e.Code, e.Value, e.Rule)
+}
+
+// Unwrap returns ErrStatic if static param was changed after Freeze.
+func (e *ValidationError) Unwrap() error {
+	if e.Rule == "static" {
+		return ErrStatic
+	}
+	return nil
+}
+
+// rule checks candidate value v.
+type rule struct {
+	name  string
+	check func(v Valuer) bool
+}
+
+// validator holds rules of a single param.
+type validator struct {
+	code  string
+	mux   sync.RWMutex
+	rules []rule
+}
+
+// validatorOf returns validator of the shared memory or nil
+// if param has no rules. c can be nil.
+func (c *Config) validatorOf(mem unsafe.Pointer) *validator {
+	if c == nil || atomic.LoadInt32(&c.validated) == 0 {
+		return nil
+	}
+	v, ok := c.validators.Load(mem)
+	if !ok {
+		return nil
+	}
+	return v.(*validator)
+}
+
+// validate checks candidate value v against rules of shared memory mem.
+func (c *Config) validate(mem unsafe.Pointer, v Valuer) error {
+	if vr := c.validatorOf(mem); vr != nil {
+		return vr.check(v)
+	}
+	return nil
+}
+
+func (vr *validator) check(v Valuer) error {
+	vr.mux.RLock()
+	defer vr.mux.RUnlock()
+	for _, r := range vr.rules {
+		if !r.check(v) {
+			return &ValidationError{Code: vr.code, Rule: r.name, Value: fmt.Sprint(v)}
+		}
+	}
+	return nil
+}
+
+func (vr *validator) add(r rule) {
+	vr.mux.Lock()
+	defer vr.mux.Unlock()
+	for _, x := range vr.rules {
+		if x.name == r.name {
+			return
+		}
+	}
+	vr.rules = append(vr.rules, r)
+}
+
+// addRules parses validation tags of struct field and attaches rules
+// to param identified by code. Returns rules added.
+func (c *Config) addRules(code string, p Valuer, tag reflect.StructTag) ([]rule, error) {
+	var rules []rule
+	for _, name := range validationTags {
+		arg, ok := tag.Lookup(name)
+		if !ok {
+			continue
+		}
+		r, err := makeRule(name, arg, p.Kind())
+		if err != nil {
+			return nil, fmt.Errorf("param '%s': %w", code, err)
+		}
+		if r.check != nil {
+			rules = append(rules, r)
+		}
+	}
+
+	if len(rules) == 0 {
+		return nil, nil
+	}
+
+	w, ok := p.(watchable)
+	if !ok {
+		return nil, fmt.Errorf("param '%s': validation is not supported by kind %s", code, p.Kind())
+	}
+
+	vr := c.validatorFor(code, w.mem())
+	for _, r := range rules {
+		vr.add(r)
+	}
+	return rules, nil
+}
+
+// hasRule returns true if rules contain rule named name.
+func hasRule(rules []rule, name string) bool {
+	for _, r := range rules {
+		if r.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// validatorFor returns validator of shared memory mem,
+// creates if not exists.
+func (c *Config) validatorFor(code string, mem unsafe.Pointer) *validator {
+	v, loaded := c.validators.LoadOrStore(mem, &validator{code: code})
+	if !loaded {
+		atomic.AddInt32(&c.validated, 1)
+	}
+	return v.(*validator)
+}
+
+// makeRule builds rule. min and max are applied to numeric kinds
+// and to length of strings and slices. oneof and pattern are applied
+// to every item of slices.
+func makeRule(name, arg string, ak AKind) (rule, error) {
+	r := rule{name: name + ":" + arg}
+
+	switch name {
+	case "required":
+		req, err := strconv.ParseBool(arg)
+		if err != nil {
+			return r, fmt.Errorf("invalid rule %s: %w", r.name, err)
+		}
+		if req {
+			r.name = name
+			r.check = func(v Valuer) bool {
+				s, ok := lengthOf(v)
+				return !ok || s > 0
+			}
+		}
+	case "min", "max":
+		var bound float64
+		var err error
+		if ak == ADuration {
+			var d time.Duration
+			d, err = time.ParseDuration(arg)
+			bound = float64(d)
+		} else {
+			bound, err = strconv.ParseFloat(arg, 64)
+		}
+		if err != nil {
+			return r, fmt.Errorf("invalid rule %s: %w", r.name, err)
+		}
+		if _, ok := numberOf(makeValuer(ak)); !ok {
+			if _, ok := lengthOf(makeValuer(ak)); !ok {
+				return r, fmt.Errorf("rule %s is not applicable to kind %s", r.name, ak)
+			}
+		}
+		isMin := name == "min"
+		r.check = func(v Valuer) bool {
+			n, ok := numberOf(v)
+			if !ok {
+				var l int
+				l, ok = lengthOf(v)
+				n = float64(l)
+			}
+			if !ok {
+				return true
+			}
+			if isMin {
+				return n >= bound
+			}
+			return n <= bound
+		}
+	case "oneof":
+		set := make(map[string]bool)
+		for _, s := range strings.Split(arg, "|") {
+			set[s] = true
+		}
+		r.check = func(v Valuer) bool {
+			for _, s := range itemsOf(v) {
+				if !set[s] {
+					return false
+				}
+			}
+			return true
+		}
+	case "pattern":
+		re, err := regexp.Compile(arg)
+		if err != nil {
+			return r, fmt.Errorf("invalid rule %s: %w", r.name, err)
+		}
+		r.check = func(v Valuer) bool {
+			for _, s := range itemsOf(v) {
+				if !re.MatchString(s) {
+					return false
+				}
+			}
+			return true
+		}
+	}
+	return r, nil
+}
+
+// numberOf returns value of numeric valuers.
+func numberOf(v Valuer) (float64, bool) {
+	switch x := v.(type) {
+	case *Int:
+		return float64(x.Val()), true
+	case *Float:
+		return x.Val(), true
+	case *Duration:
+		return float64(x.Val()), true
+	}
+	return 0, false
+}
+
+// lengthOf returns length of strings and slices.
+func lengthOf(v Valuer) (int, bool) {
+	switch x := v.(type) {
+	case *String:
+		return len(x.Val()), true
+	case *Secret:
+		return len(x.Reveal()), true
+	case *StringSlice:
+		return x.Len(), true
+	case *IntSlice:
+		return x.Len(), true
+	}
+	return 0, false
+}
+
+// itemsOf returns items of slices or value as a single item.
+func itemsOf(v Valuer) []string {
+	switch x := v.(type) {
+	case *StringSlice:
+		return x.Val()
+	case *IntSlice:
+		is := x.Val()
+		res := make([]string, len(is))
+		for i := range is {
+			res[i] = strconv.Itoa(is[i])
+		}
+		return res
+	}
+	return []string{Plain(v)}
+}
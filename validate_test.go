@@ -0,0 +1,117 @@
+package gonfig_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/axkit/gonfig"
+)
+
+func TestConfig_BindStructValidation(t *testing.T) {
+
+	type store struct {
+		Port     gonfig.Int         `cfg:"port" default:"8080" min:"1" max:"65535"`
+		Level    gonfig.String      `cfg:"level" default:"info" oneof:"debug|info|warn"`
+		Report   gonfig.String      `cfg:"report_url" pattern:"^https://"`
+		Token    gonfig.String      `cfg:"token" required:"true"`
+		Optional gonfig.String      `cfg:"optional" required:"false"`
+		Timeout  gonfig.Duration    `cfg:"timeout" default:"1s" max:"1m"`
+		Origins  gonfig.StringSlice `cfg:"origins" default:"https://a.com" pattern:"^https://" min:"1"`
+		Ratio    gonfig.Float       `cfg:"ratio" default:"2" max:"1"`
+		Weekdays gonfig.IntSlice    `cfg:"weekdays" default:"1,2" oneof:"1|2|3|4|5|6|7"`
+	}
+
+	var a store
+	cfg := gonfig.New()
+	cfg.MustParam("report_url", gonfig.AString).Parse("http://report.local")
+
+	errs := cfg.BindStruct(&a)
+	failed := make(map[string]string)
+	for _, err := range errs {
+		var ve *gonfig.ValidationError
+		if !errors.As(err, &ve) {
+			t.Errorf("unexpected error: %v", err)
+			continue
+		}
+		failed[ve.Code] = ve.Rule
+	}
+
+	expected := map[string]string{
+		"report_url": "pattern:^https://",
+		"token":      "required",
+		"ratio":      "max:1",
+	}
+	if len(failed) != len(expected) {
+		t.Errorf("unexpected bind errors: %v", errs)
+	}
+	for code, rule := range expected {
+		if failed[code] != rule {
+			t.Errorf("param '%s': expected rule %s, got %s", code, rule, failed[code])
+		}
+	}
+
+	cases := []struct {
+		code string
+		val  string
+		ok   bool
+	}{
+		{"port", "-1", false},
+		{"port", "70000", false},
+		{"port", "443", true},
+		{"level", "trace", false},
+		{"level", "debug", true},
+		{"report_url", "https://report.local", true},
+		{"token", "", false},
+		{"token", "secret", true},
+		{"optional", "", true},
+		{"timeout", "2m", false},
+		{"timeout", "30s", true},
+		{"origins", "", false},
+		{"origins", "https://b.com,http://c.com", false},
+		{"origins", "https://b.com,https://c.com", true},
+		{"weekdays", "1,8", false},
+		{"weekdays", "6,7", true},
+	}
+
+	for _, c := range cases {
+		p, _ := cfg.Get(c.code)
+		before := p.(interface{ String() string }).String()
+		err := p.Parse(c.val)
+		if c.ok && err != nil {
+			t.Errorf("param '%s': unexpected error %v", c.code, err)
+		}
+		if !c.ok {
+			if err == nil {
+				t.Errorf("param '%s': value '%s' must be rejected", c.code, c.val)
+			} else if after := p.(interface{ String() string }).String(); after != before {
+				t.Errorf("param '%s': rejected value changed %s to %s", c.code, before, after)
+			}
+		}
+	}
+
+	if a.Port.Val() != 443 || a.Level.Val() != "debug" || a.Origins.Len() != 2 {
+		t.Error("valid values are not applied")
+	}
+
+	// Set is not validated.
+	a.Port.Set(-1)
+	if a.Port.Val() != -1 {
+		t.Error("Set failed")
+	}
+}
+
+func TestConfig_BindStructInvalidRule(t *testing.T) {
+
+	type store struct {
+		Port  gonfig.Int  `cfg:"port" min:"abc"`
+		Debug gonfig.Bool `cfg:"debug" max:"1"`
+		Name  struct {
+			First gonfig.String `cfg:"first" pattern:"("`
+		}
+	}
+
+	var a store
+	if errs := gonfig.New().BindStruct(&a); len(errs) != 3 {
+		t.Errorf("expected 3 errors, got %v", errs)
+	}
+}
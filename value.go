@@ -0,0 +1,188 @@
+package gonfig
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"sync/atomic"
+	"unsafe"
+)
+
+// ErrUnknownKind raises when parameter kind is not built-in
+// and was not registered by RegisterKind.
+var ErrUnknownKind = errors.New("unknown value kind")
+
+// Value implements Valuer as atomic value of arbitrary type T.
+// Type T must be registered by RegisterKind before usage.
+// Value must not be copied after first use.
+type Value[T any] struct {
+	ref atomic.Pointer[atomic.Pointer[T]]
+
+	// c is container the param belongs to.
+	c atomic.Pointer[Config]
+}
+
+// NewValue returns atomic value of type T.
+func NewValue[T any]() *Value[T] {
+	a := new(Value[T])
+	a.ref.Store(new(atomic.Pointer[T]))
+	return a
+}
+
+func (a *Value[T]) desc() (*kindDesc, bool) {
+	return kindByType(reflect.TypeOf((*T)(nil)).Elem())
+}
+
+// Kind returns AKind returned by RegisterKind for type T.
+// Returns Unknown if T is not registered.
+func (a *Value[T]) Kind() AKind {
+	if d, ok := a.desc(); ok {
+		return d.kind
+	}
+	return Unknown
+}
+
+// Set assigns value atomically. Initializes if was not before.
+func (a *Value[T]) Set(v T) {
+	if a.owner().rejected(a.mem(), func() Valuer {
+		n := NewValue[T]()
+		n.Set(v)
+		return n
+	}) {
+		return
+	}
+	a.set(v, nil)
+}
+
+// set is like Set, the change is attributed to by.
+func (a *Value[T]) set(v T, by *author) {
+	ptr := a.ref.Load()
+	if n := a.owner().notifierOf(unsafe.Pointer(ptr)); n != nil {
+		n.change(by, func() (string, string, bool) {
+			var old T
+			if op := ptr.Swap(&v); op != nil {
+				old = *op
+			}
+			olds, news := a.format(old), a.format(v)
+			return olds, news, olds != news
+		})
+		return
+	}
+	if ptr == nil {
+		a.ref.CompareAndSwap(nil, new(atomic.Pointer[T]))
+		ptr = a.ref.Load()
+	}
+	ptr.Store(&v)
+}
+
+// Val returns value atomically. Returns zero value of T
+// if it's not binded to params container.
+func (a *Value[T]) Val() T {
+	var zero T
+	ptr := a.ref.Load()
+	if ptr == nil {
+		return zero
+	}
+	v := ptr.Load()
+	if v == nil {
+		return zero
+	}
+	return *v
+}
+
+// IsBinded returns true if Value bineded to params container.
+func (a *Value[T]) IsBinded() bool {
+	return a.ref.Load() != nil
+}
+
+// Bind binds current atomic variable to variable identified by to.
+// As a result two Value address to the same variable.
+func (a *Value[T]) Bind(to *Value[T]) {
+	if ptr := to.ref.Load(); ptr != nil {
+		a.c.Store(to.c.Load())
+		a.ref.Store(ptr)
+	}
+}
+
+func (a *Value[T]) mem() unsafe.Pointer {
+	return unsafe.Pointer(a.ref.Load())
+}
+
+func (a *Value[T]) owner() *Config {
+	return a.c.Load()
+}
+
+func (a *Value[T]) own(c *Config) {
+	a.c.Store(c)
+}
+
+func (a *Value[T]) assign(v Valuer, by *author) {
+	a.set(v.(*Value[T]).Val(), by)
+}
+
+// bindValuer implements binder interface.
+func (a *Value[T]) bindValuer(to Valuer) bool {
+	v, ok := to.(*Value[T])
+	if ok && !a.IsBinded() {
+		a.Bind(v)
+	}
+	return ok
+}
+
+// String implements Stringer interface. Uses formatter
+// given to RegisterKind.
+func (a *Value[T]) String() string {
+	return a.format(a.Val())
+}
+
+func (a *Value[T]) format(v T) string {
+	d, ok := a.desc()
+	if !ok {
+		return ""
+	}
+	return d.format.(func(T) string)(v)
+}
+
+// Parse converts input argument using parser given to RegisterKind
+// and assigns to value. Value is not changed if it violates
+// validation rules.
+func (a *Value[T]) Parse(s string) error {
+	d, ok := a.desc()
+	if !ok {
+		return ErrUnknownKind
+	}
+
+	v, err := d.parse.(func(string) (T, error))(s)
+	if err != nil {
+		return err
+	}
+
+	if vr := a.owner().validatorOf(a.mem()); vr != nil {
+		n := NewValue[T]()
+		n.Set(v)
+		if err := vr.check(n); err != nil {
+			return err
+		}
+	}
+	a.Set(v)
+	return nil
+}
+
+// MarshalJSON implement Marshaller interface. Value is
+// marshaled as string returned by String.
+func (a *Value[T]) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Quote(a.String())), nil
+}
+
+// UnmarshalJSON implement Unmarshaller interface.
+func (a *Value[T]) UnmarshalJSON(buf []byte) error {
+	s := string(buf)
+	if len(s) >= 2 && s[0] == '"' {
+		v, err := strconv.Unquote(s)
+		if err != nil {
+			return err
+		}
+		s = v
+	}
+	return a.Parse(s)
+}